@@ -21,6 +21,10 @@ const (
 	optionPreviewHeigh    = `preview-height`
 	optionPreviewGenerate = `preview-generate`
 	optionToc             = `toc`
+	optionSitemap         = `sitemap`
+	optionSitemapPriority = `sitemap-priority`
+	optionSitemapChange   = `sitemap-changefreq`
+	optionAutoIndex       = `auto-index`
 )
 
 var accoutrementActions = map[string]func(string, *yunyun.Accoutrement){
@@ -34,6 +38,10 @@ var accoutrementActions = map[string]func(string, *yunyun.Accoutrement){
 	optionPreviewHeigh:    accoutrementPreviewHeight,
 	optionPreviewGenerate: accoutrementPreviewGenerate,
 	optionToc:             accoutrementToc,
+	optionSitemap:         accoutrementSitemap,
+	optionSitemapPriority: accoutrementSitemapPriority,
+	optionSitemapChange:   accoutrementSitemapChangefreq,
+	optionAutoIndex:       accoutrementAutoIndex,
 }
 
 // InitializeAccoutrement fills accoutrement according to the config
@@ -130,6 +138,26 @@ func accoutrementToc(what string, target *yunyun.Accoutrement) {
 	accoutrementBool(what, &target.Toc)
 }
 
+// accoutrementSitemap sets the sitemap option of the accoutrement.
+func accoutrementSitemap(what string, target *yunyun.Accoutrement) {
+	accoutrementBool(what, &target.Sitemap)
+}
+
+// accoutrementSitemapPriority sets the sitemap priority option of the accoutrement.
+func accoutrementSitemapPriority(what string, target *yunyun.Accoutrement) {
+	target.SitemapPriority = what
+}
+
+// accoutrementSitemapChangefreq sets the sitemap change frequency option of the accoutrement.
+func accoutrementSitemapChangefreq(what string, target *yunyun.Accoutrement) {
+	target.SitemapChangefreq = what
+}
+
+// accoutrementAutoIndex sets the auto index option of the accoutrement.
+func accoutrementAutoIndex(what string, target *yunyun.Accoutrement) {
+	accoutrementBool(what, &target.AutoIndex)
+}
+
 // accoutrementBool sets the bool value of the target according to the what.
 func accoutrementBool(what string, target *yunyun.AccoutrementFlip) {
 	switch strings.TrimSpace(what) {
@@ -12,6 +12,7 @@ import (
 	"github.com/thecsw/darkness/emilia/puck"
 	"github.com/thecsw/darkness/yunyun"
 	"github.com/thecsw/gana"
+	"github.com/thecsw/rei"
 )
 
 // BuildConfig builds the config from the passed options.
@@ -29,6 +30,9 @@ func BuildConfig(options Options) *DarknessConfig {
 		conf.Runtime.Logger.Info("Guessing working directory", "result", conf.Runtime.WorkDir)
 	}
 
+	// Remember where the config is, so it's never mistaken for a site file.
+	conf.Runtime.ConfigFile, _ = filepath.Abs(options.DarknessConfig)
+
 	// Read the config file.
 	data, err := os.ReadFile(options.DarknessConfig)
 	if err != nil && !options.Test {
@@ -41,6 +45,15 @@ func BuildConfig(options Options) *DarknessConfig {
 		conf.Runtime.Logger.Fatal("Decoding config", "path", options.DarknessConfig, "err", err)
 	}
 
+	// The output directory gets removed when cleaning, so it can't be
+	// the project itself or anything outside of it.
+	if !isUnset(conf.Project.OutputDirectory) {
+		if err := conf.Project.checkOutputDirectory(conf.Runtime.WorkDir); err != nil {
+			conf.Runtime.Logger.Fatal("Bad output directory", "path", conf.Project.OutputDirectory, "err", err)
+		}
+		conf.Project.OutputDirectory = yunyun.RelativePathDir(filepath.Clean(string(conf.Project.OutputDirectory)))
+	}
+
 	// Define the preview filename.
 	if isUnset(conf.Website.Preview) {
 		conf.Website.Preview = puck.DefaultPreviewFile
@@ -56,6 +69,10 @@ func BuildConfig(options Options) *DarknessConfig {
 			conf.Runtime.Logger.Error("Getting working directory, no config url found", "err", err)
 			os.Exit(1)
 		}
+		// Local paths should point to the built files.
+		if !isUnset(conf.Project.OutputDirectory) {
+			conf.Url = filepath.Join(conf.Url, string(conf.Project.OutputDirectory))
+		}
 	}
 	conf.Runtime.isUrlLocal = !yunyun.UrlRegexp.MatchString(conf.Url)
 
@@ -87,6 +104,53 @@ func BuildConfig(options Options) *DarknessConfig {
 		conf.Website.SyntaxHighlightingTheme = highlightJsThemeDefaultPath
 	}
 
+	// Output directory is resolved against the working directory.
+	conf.Project.workDir = conf.Runtime.WorkDir
+
+	// Pages are in english unless told otherwise.
+	if isUnset(conf.Website.Language) {
+		conf.Website.Language = puck.DefaultLanguage
+	}
+
+	// Set the default templates directory if it's not set.
+	if isUnset(conf.Project.Templates) {
+		conf.Project.Templates = puck.DefaultTemplatesDirectory
+	}
+
+	// Set the default sitemap filename if it's not set.
+	if isUnset(conf.Sitemap.Filename) {
+		conf.Sitemap.Filename = puck.DefaultSitemapFilename
+	}
+
+	// Set the default search filenames if they're not set.
+	if isUnset(conf.Search.Filename) {
+		conf.Search.Filename = puck.DefaultSearchFilename
+	}
+	if isUnset(conf.Search.Widget) {
+		conf.Search.Widget = puck.DefaultSearchWidgetFilename
+	}
+
+	// Set the default tags directory and title if they're not set.
+	if isUnset(conf.Tags.Directory) {
+		conf.Tags.Directory = puck.DefaultTagsDirectory
+	}
+	if isUnset(conf.Tags.Title) {
+		conf.Tags.Title = puck.DefaultTagsTitle
+	}
+
+	// Set the default archive directory and title if they're not set.
+	if isUnset(conf.Archive.Directory) {
+		conf.Archive.Directory = puck.DefaultArchiveDirectory
+	}
+	if isUnset(conf.Archive.Title) {
+		conf.Archive.Title = puck.DefaultArchiveTitle
+	}
+
+	// Set the default link graph filename if it's not set.
+	if isUnset(conf.Backlinks.Graph) {
+		conf.Backlinks.Graph = puck.DefaultGraphFilename
+	}
+
 	// Set the default vendor directory if it's not set.
 	if isUnset(conf.Project.DarknessVendorDirectory) {
 		conf.Project.DarknessVendorDirectory = puck.DefaultVendorDirectory
@@ -127,10 +191,54 @@ func BuildConfig(options Options) *DarknessConfig {
 	// Set up the gallery vendoring.
 	conf.setupGalleryVendoring(options)
 
+	// Pages only reload themselves when they're served.
+	conf.Runtime.LiveReload = options.LiveReload
+
+	// Remember what the pages are built with, so caches know when to expire.
+	conf.Runtime.ConfigHash = rei.Sha256([]byte(fmt.Sprintf("%s|%s|%v|%t|%t",
+		data, conf.Url, conf.Project.Outputs, conf.Runtime.VendorGalleries, conf.Runtime.LiveReload)))
+
 	// Set up the gallery vendoring.
 	return conf
 }
 
+// CheckConfig returns an error if the config can't be read or decoded,
+// which is useful to check before rebuilding the config with `BuildConfig`.
+func CheckConfig(options Options) error {
+	data, err := os.ReadFile(options.DarknessConfig)
+	if err != nil {
+		return fmt.Errorf("opening config %s: %v", options.DarknessConfig, err)
+	}
+	conf := &DarknessConfig{}
+	if _, err := toml.Decode(string(data), conf); err != nil {
+		return fmt.Errorf("decoding config %s: %v", options.DarknessConfig, err)
+	}
+	// Same as `BuildConfig`, which would exit on a bad output directory.
+	workDir := WorkingDirectory(options.WorkDir)
+	if isUnset(workDir) {
+		workDir = WorkingDirectory(filepath.Dir(options.DarknessConfig))
+	}
+	if !isUnset(conf.Project.OutputDirectory) {
+		if err := conf.Project.checkOutputDirectory(workDir); err != nil {
+			return fmt.Errorf("checking config %s: %v", options.DarknessConfig, err)
+		}
+	}
+	return nil
+}
+
+// checkOutputDirectory returns an error if the output directory is not
+// strictly inside the working directory.
+func (p ProjectConfig) checkOutputDirectory(workDir WorkingDirectory) error {
+	dir := filepath.Clean(string(p.OutputDirectory))
+	if filepath.IsAbs(dir) {
+		return fmt.Errorf("output directory %s must be relative to the project", p.OutputDirectory)
+	}
+	if !workDir.Contains(workDir.JoinGeneric(dir)) {
+		return fmt.Errorf("output directory %s must be inside the project", p.OutputDirectory)
+	}
+	return nil
+}
+
 // isUnset returns true if the passed value is a zero value of its type.
 func isUnset[T comparable](what T) bool {
 	return what == gana.ZeroValue[T]()
@@ -0,0 +1,33 @@
+package alpha
+
+import (
+	"testing"
+
+	"github.com/thecsw/darkness/yunyun"
+)
+
+func TestCheckOutputDirectory(t *testing.T) {
+	workDir := WorkingDirectory(t.TempDir())
+	tests := []struct {
+		dir string
+		ok  bool
+	}{
+		{"public", true},
+		{"./public", true},
+		{"build/site", true},
+		{"public/../site", true},
+		{".", false},
+		{"./", false},
+		{"public/..", false},
+		{"..", false},
+		{"../public", false},
+		{"/tmp/public", false},
+		{string(workDir) + "/public", false},
+	}
+	for _, test := range tests {
+		err := ProjectConfig{OutputDirectory: yunyun.RelativePathDir(test.dir)}.checkOutputDirectory(workDir)
+		if ok := err == nil; ok != test.ok {
+			t.Errorf("output directory %q: got err %v, want ok %t", test.dir, err, test.ok)
+		}
+	}
+}
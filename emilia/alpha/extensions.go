@@ -1,18 +1,40 @@
 package alpha
 
 import (
+	"strings"
+
 	"github.com/thecsw/darkness/emilia/puck"
+	"github.com/thecsw/gana"
 )
 
 // setupProjectExtensions sets up the input/output extensions for the project.
 func (conf *DarknessConfig) setupProjectExtensions(options Options) {
+	// Make sure all the extensions start with a dot.
+	conf.Project.Input = withDot(conf.Project.Input)
+	conf.Project.Output = withDot(conf.Project.Output)
+	conf.Project.Inputs = gana.Map(withDot, conf.Project.Inputs)
+	conf.Project.Outputs = gana.Map(withDot, conf.Project.Outputs)
+
+	// If multiple inputs are given, the first one is the main input.
+	if isUnset(conf.Project.Input) && len(conf.Project.Inputs) > 0 {
+		conf.Project.Input = gana.First(conf.Project.Inputs)
+	}
+
 	// If input/output formats are empty, default to .org/.html respectively.
 	if isUnset(conf.Project.Input) {
 		conf.Runtime.Logger.Warn("Input format not found, using a default", "ext", puck.ExtensionOrgmode)
 		conf.Project.Input = puck.ExtensionOrgmode
 	}
 
-	// Output section.
+	// The main input should always be one of the inputs.
+	if !gana.Any(conf.Project.Input, conf.Project.Inputs) {
+		conf.Project.Inputs = append([]string{conf.Project.Input}, conf.Project.Inputs...)
+	}
+
+	// Output section, where the first of multiple outputs is the main one.
+	if isUnset(conf.Project.Output) && len(conf.Project.Outputs) > 0 {
+		conf.Project.Output = gana.First(conf.Project.Outputs)
+	}
 	if isUnset(conf.Project.Output) {
 		conf.Runtime.Logger.Warn("Input format not found, using a default", "ext", puck.ExtensionHtml)
 		conf.Project.Output = puck.ExtensionHtml
@@ -23,5 +45,19 @@ func (conf *DarknessConfig) setupProjectExtensions(options Options) {
 	if !isUnset(options.OutputExtension) && conf.Project.Output != options.OutputExtension {
 		conf.Runtime.Logger.Warn("Output extension was overwritten", "ext", options.OutputExtension)
 		conf.Project.Output = options.OutputExtension
+		conf.Project.Outputs = nil
+	}
+
+	// The main output should always be one of the outputs.
+	if !gana.Any(conf.Project.Output, conf.Project.Outputs) {
+		conf.Project.Outputs = append([]string{conf.Project.Output}, conf.Project.Outputs...)
+	}
+}
+
+// withDot prepends a dot to the extension if it's missing.
+func withDot(ext string) string {
+	if isUnset(ext) || strings.HasPrefix(ext, ".") {
+		return ext
 	}
+	return "." + ext
 }
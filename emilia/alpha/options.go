@@ -22,4 +22,7 @@ type Options struct {
 
 	// VendorGalleries dictates whether we should stub in local gallery images.
 	VendorGalleries bool
+
+	// LiveReload adds the live reload script to the pages.
+	LiveReload bool
 }
@@ -40,6 +40,25 @@ func (workDir WorkingDirectory) Rel(filename yunyun.FullPathFile) yunyun.Relativ
 	return yunyun.RelativePathFile(strings.TrimPrefix(string(filename), string(workDir+`/`)))
 }
 
+// Contains returns true if the target is strictly below the working
+// directory, so the directory itself or anything outside of it is never
+// mistaken for one of its files.
+func (workDir WorkingDirectory) Contains(target string) bool {
+	root, err := filepath.Abs(string(workDir))
+	if err != nil {
+		return false
+	}
+	full, err := filepath.Abs(target)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(root, full)
+	if err != nil {
+		return false
+	}
+	return rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
 // PackRel cleans the filename from absolute workspace prefix.
 func (workDir WorkingDirectory) PackRel(filename yunyun.FullPathFile, data string) (yunyun.RelativePathFile, string) {
 	return workDir.Rel(filename), data
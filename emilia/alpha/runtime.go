@@ -15,6 +15,13 @@ type RuntimeConfig struct {
 	// WorkDir is the directory of where darkness project lives.
 	WorkDir WorkingDirectory
 
+	// ConfigFile is the location of the config file that was read.
+	ConfigFile string
+
+	// ConfigHash is the hash of the config and the options that
+	// affect how pages are built.
+	ConfigHash string
+
 	// Slice with just `Url` in it.
 	urlSlice []string
 
@@ -28,6 +35,10 @@ type RuntimeConfig struct {
 	// of remote links in galleries.
 	VendorGalleries bool
 
+	// LiveReload tells us if the pages should reload themselves
+	// when the server rebuilds them.
+	LiveReload bool
+
 	// HtmlHighlightLanguages is a map of languages that we want to
 	// highlight in HTML.
 	HtmlHighlightLanguages map[string]struct{}
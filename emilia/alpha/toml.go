@@ -20,6 +20,30 @@ type DarknessConfig struct {
 	// RSS is the rss config.
 	RSS RSSConfig `toml:"rss"`
 
+	// Sitemap is the sitemap config.
+	Sitemap SitemapConfig `toml:"sitemap"`
+
+	// Search is the search index config.
+	Search SearchConfig `toml:"search"`
+
+	// Tags is the tags config.
+	Tags TagsConfig `toml:"tags"`
+
+	// Archive is the archive config.
+	Archive ArchiveConfig `toml:"archive"`
+
+	// Pagination is the pagination config.
+	Pagination PaginationConfig `toml:"pagination"`
+
+	// Siblings is the previous/next navigation config.
+	Siblings SiblingsConfig `toml:"siblings"`
+
+	// Backlinks is the backlinks config.
+	Backlinks BacklinksConfig `toml:"backlinks"`
+
+	// Check is the check config.
+	Check LinkCheckConfig `toml:"check"`
+
 	// Author is the author section of the config
 	Author AuthorConfig `toml:"author"`
 
@@ -39,9 +63,26 @@ type ProjectConfig struct {
 	// Input is the input format (default ".org")
 	Input string `toml:"input"`
 
+	// Inputs are all the input formats of a mixed-format project,
+	// each file is parsed according to its extension (defaults to `Input`).
+	Inputs []string `toml:"inputs"`
+
 	// Output is the output format (defaulte ".html")
 	Output string `toml:"output"`
 
+	// Templates is the directory with the html templates that
+	// override the default ones, defaults to "templates"
+	Templates yunyun.RelativePathDir `toml:"templates"`
+
+	// Outputs are all the output formats built from each page, the
+	// first one is the main output if `output` is not given
+	Outputs []string `toml:"outputs"`
+
+	// OutputDirectory is where the built site goes, mirroring the source
+	// tree with all the static assets. If empty, outputs are written
+	// next to their sources.
+	OutputDirectory yunyun.RelativePathDir `toml:"output_dir"`
+
 	// DarknessVendorDirectory where to vendor, default to `darkness_vendor`.
 	DarknessVendorDirectory yunyun.RelativePathDir `toml:"vendor_directory"`
 
@@ -52,6 +93,13 @@ type ProjectConfig struct {
 	Exclude []yunyun.RelativePathDir `toml:"exclude"`
 
 	ExcludeEnabled bool `toml:"-"`
+
+	// Ignore are the glob patterns of the files that are not copied to the
+	// output directory, on top of the default ones like READMEs and licenses.
+	Ignore []string `toml:"ignore"`
+
+	// workDir is the working directory used to resolve the output directory.
+	workDir WorkingDirectory
 }
 
 // WebsiteConfig is the website section of the config
@@ -72,6 +120,9 @@ type WebsiteConfig struct {
 	// Locale is the locale of the site
 	Locale string `toml:"locale"`
 
+	// Language is the language of the pages, defaults to "en"
+	Language string `toml:"language"`
+
 	// SyntaxHighlightingTheme decides what theme to use from highlight.js
 	SyntaxHighlightingTheme yunyun.RelativePathFile `toml:"syntax_highlighting_theme"`
 
@@ -182,4 +233,105 @@ type RSSConfig struct {
 	Category string `toml:"category"`
 	// If true, darkness will add the rss icon to the menu.
 	Enable bool `toml:"enable"`
+	// FullContent embeds whole pages in the feeds, instead of
+	// only their descriptions.
+	FullContent bool `toml:"full_content"`
+	// Feeds are the feeds that misa generates in one go.
+	Feeds []FeedConfig `toml:"feeds"`
+}
+
+// FeedConfig is a single feed in `[[rss.feeds]]`.
+type FeedConfig struct {
+	// Filename is where the feed is written, relative to the project.
+	Filename string `toml:"filename"`
+	// Directories are the directories to look for pages in, all if empty.
+	Directories []string `toml:"directories"`
+	// Title is the title of the feed, defaults to the site's title.
+	Title string `toml:"title"`
+	// Description is the description of the feed, defaults to the
+	// description of the root page or of the rss section.
+	Description string `toml:"description"`
+	// Limit is the maximum number of items, no limit if zero.
+	Limit int `toml:"limit"`
+	// Format is one of "rss", "atom", or "json", guessed from
+	// the filename's extension if empty.
+	Format string `toml:"format"`
+	// Categories also generates a feed (with the same filename, where
+	// slashes become dashes) in every category's directory, with only
+	// that category's pages.
+	Categories bool `toml:"categories"`
+}
+
+// SitemapConfig is the sitemap section of the config.
+type SitemapConfig struct {
+	// Enable makes the build write the sitemap.
+	Enable bool `toml:"enable"`
+	// Filename is where the sitemap is written, defaults to "sitemap.xml".
+	Filename yunyun.RelativePathFile `toml:"filename"`
+	// Robots also writes robots.txt, which points to the sitemap.
+	Robots bool `toml:"robots"`
+	// Disallow are the paths that robots.txt asks crawlers to stay away from.
+	Disallow []string `toml:"disallow"`
+}
+
+// SearchConfig is the search section of the config.
+type SearchConfig struct {
+	// Enable makes the build write the search index and the widget.
+	Enable bool `toml:"enable"`
+	// Filename is where the index is written, defaults to "search.json".
+	Filename yunyun.RelativePathFile `toml:"filename"`
+	// Widget is where the search widget is written, defaults to "search.js".
+	Widget yunyun.RelativePathFile `toml:"widget"`
+}
+
+// TagsConfig is the tags section of the config.
+type TagsConfig struct {
+	// Enable makes the build generate a listing page for every tag.
+	Enable bool `toml:"enable"`
+	// Directory is where the tag pages are generated, defaults to "tags".
+	Directory yunyun.RelativePathDir `toml:"directory"`
+	// Title is the title of the page that lists all the tags, defaults to "Tags".
+	Title string `toml:"title"`
+}
+
+// ArchiveConfig is the archive section of the config.
+type ArchiveConfig struct {
+	// Enable makes the build generate the year and month archive pages.
+	Enable bool `toml:"enable"`
+	// Directory is where the archive pages are generated, defaults to "archive".
+	Directory yunyun.RelativePathDir `toml:"directory"`
+	// Title is the title of the page that lists all the years, defaults to "Archive".
+	Title string `toml:"title"`
+}
+
+// SiblingsConfig is the siblings section of the config.
+type SiblingsConfig struct {
+	// Enable links every dated page to the previous and next dated pages
+	// in its directory, pages in a series are linked within it regardless.
+	Enable bool `toml:"enable"`
+}
+
+// BacklinksConfig is the backlinks section of the config.
+type BacklinksConfig struct {
+	// Enable lists the pages linking to every page, and writes the graph
+	// of all the internal links.
+	Enable bool `toml:"enable"`
+	// Graph is where the link graph is written, defaults to "graph.json".
+	Graph yunyun.RelativePathFile `toml:"graph"`
+}
+
+// LinkCheckConfig is the check section of the config.
+type LinkCheckConfig struct {
+	// External also requests the links to other websites.
+	External bool `toml:"external"`
+	// Endpoint is asked about external links instead of the links
+	// themselves, with the link in the `url` query parameter.
+	Endpoint string `toml:"endpoint"`
+}
+
+// PaginationConfig is the pagination section of the config.
+type PaginationConfig struct {
+	// Size is the number of pages listed on every page of a generated
+	// listing, the whole listing is on one page if it's not set.
+	Size int `toml:"size"`
 }
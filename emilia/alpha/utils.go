@@ -1,12 +1,103 @@
 package alpha
 
 import (
+	"path/filepath"
 	"strings"
 
+	"github.com/thecsw/darkness/emilia/puck"
 	"github.com/thecsw/darkness/yunyun"
+	"github.com/thecsw/gana"
 )
 
 // InputFilenameToOutput converts input filename to the filename to write.
 func (p ProjectConfig) InputFilenameToOutput(file yunyun.FullPathFile) string {
-	return strings.Replace(string(file), p.Input, p.Output, 1)
+	return p.InputFilenameToOutputWith(file, p.Output)
+}
+
+// InputFilenameToOutputWith converts input filename to the filename to
+// write with the given output extension.
+func (p ProjectConfig) InputFilenameToOutputWith(file yunyun.FullPathFile, ext string) string {
+	output := yunyun.FullPathFile(strings.TrimSuffix(string(file), filepath.Ext(string(file))) + ext)
+	return string(p.SourceToOutput(output))
+}
+
+// InputFilenameToOutputs converts input filename to all the filenames to write.
+func (p ProjectConfig) InputFilenameToOutputs(file yunyun.FullPathFile) []string {
+	return gana.Map(func(ext string) string { return p.InputFilenameToOutputWith(file, ext) }, p.Outputs)
+}
+
+// SourceToOutput converts a file in the source tree to its location in
+// the output directory, returns the file itself if there is none.
+func (p ProjectConfig) SourceToOutput(file yunyun.FullPathFile) yunyun.FullPathFile {
+	if !p.HasOutputDirectory() {
+		return file
+	}
+	return p.workDir.Join(yunyun.JoinRelativePaths(p.OutputDirectory, p.workDir.Rel(file)))
+}
+
+// HasOutputDirectory returns true if a separate output directory is used.
+func (p ProjectConfig) HasOutputDirectory() bool {
+	return !isUnset(p.OutputDirectory)
+}
+
+// OutputRoot returns the full path of the directory with the built site.
+func (p ProjectConfig) OutputRoot() yunyun.FullPathDir {
+	return yunyun.FullPathDir(p.workDir.Join(yunyun.RelativePathFile(p.OutputDirectory)))
+}
+
+// IsIgnored returns true if the file is not a part of the site, so it's
+// not copied to the output directory. Patterns are matched against both
+// the file's name and its path relative to the project.
+func (p ProjectConfig) IsIgnored(file yunyun.FullPathFile) bool {
+	relative := string(p.workDir.Rel(file))
+	return gana.Anyf(func(pattern string) bool {
+		matchedName, _ := filepath.Match(pattern, filepath.Base(relative))
+		matchedPath, _ := filepath.Match(pattern, relative)
+		return matchedName || matchedPath
+	}, append(append([]string{}, puck.DefaultIgnoredAssets...), p.Ignore...))
+}
+
+// IsTemplate returns true if the file is in the templates directory.
+func (p ProjectConfig) IsTemplate(file yunyun.FullPathFile) bool {
+	templates := filepath.Clean(string(p.Templates))
+	return strings.HasPrefix(string(p.workDir.Rel(file)), templates+string(filepath.Separator))
+}
+
+// IsOutput returns true if the file has one of the project's output extensions.
+func (p ProjectConfig) IsOutput(file string) bool {
+	return gana.Any(filepath.Ext(file), p.Outputs)
+}
+
+// IsInput returns true if the file has one of the project's input extensions.
+func (p ProjectConfig) IsInput(file string) bool {
+	return gana.Any(filepath.Ext(file), p.Inputs)
+}
+
+// GeneratedFiles returns the files that the build writes by itself, like
+// feeds, sitemaps, and search indices, relative to the project.
+func (conf *DarknessConfig) GeneratedFiles() []yunyun.RelativePathFile {
+	files := make([]yunyun.RelativePathFile, 0, 8)
+	for _, feed := range conf.RSS.Feeds {
+		files = append(files, yunyun.RelativePathFile(feed.Filename))
+	}
+	if conf.Sitemap.Enable {
+		files = append(files, conf.Sitemap.Filename)
+	}
+	if conf.Sitemap.Robots {
+		files = append(files, puck.RobotsFilename)
+	}
+	if conf.Search.Enable {
+		files = append(files, conf.Search.Filename, conf.Search.Widget)
+	}
+	if conf.Backlinks.Enable {
+		files = append(files, conf.Backlinks.Graph)
+	}
+	return gana.Map(func(file yunyun.RelativePathFile) yunyun.RelativePathFile {
+		return yunyun.RelativePathFile(filepath.Clean(string(file)))
+	}, files)
+}
+
+// IsGenerated returns true if the file is written by the build itself.
+func (conf *DarknessConfig) IsGenerated(file yunyun.RelativePathFile) bool {
+	return gana.Any(yunyun.RelativePathFile(filepath.Clean(string(file))), conf.GeneratedFiles())
 }
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/thecsw/darkness/emilia/puck"
+	"github.com/thecsw/darkness/yunyun"
 )
 
 const (
@@ -28,6 +29,30 @@ func ConvertHoloscene(HEtime string) time.Time {
 	return getHoloscene(matches[0][1], matches[0][2])
 }
 
+// PageDate returns the page's date and true if it has one. Pages without
+// dates are either empty or have placeholder dates from the parsers, like
+// `0; 12000 H.E.`, neither of which is a real date.
+func PageDate(page *yunyun.Page) (time.Time, bool) {
+	return realDate(page.Date)
+}
+
+// PageUpdated returns the date the page was last updated and true if
+// the page declares one, same rules as `PageDate` apply.
+func PageUpdated(page *yunyun.Page) (time.Time, bool) {
+	return realDate(page.Updated)
+}
+
+// realDate converts the holoscene time and tells whether it's a real date,
+// the parsers use the day zero when there is none.
+func realDate(holoscene string) (time.Time, bool) {
+	matches := puck.HEregex.FindStringSubmatch(holoscene)
+	if len(matches) < 1 {
+		return time.Time{}, false
+	}
+	day, _ := strconv.Atoi(matches[1])
+	return getHoloscene(matches[1], matches[2]), day > 0
+}
+
 // getHoloscene returns a time struct for a given holoscene time.
 func getHoloscene(dayS, yearS string) time.Time {
 	// By the regex, we are guaranteed to have good numbers
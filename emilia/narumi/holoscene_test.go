@@ -0,0 +1,35 @@
+package narumi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thecsw/darkness/yunyun"
+)
+
+func TestPageDate(t *testing.T) {
+	tests := []struct {
+		date  string
+		want  time.Time
+		found bool
+	}{
+		{"10; 12023 H.E.", time.Date(2023, time.January, 10, 0, 0, 0, 0, time.Local), true},
+		{"31; 12023 H.E.", time.Date(2023, time.January, 31, 0, 0, 0, 0, time.Local), true},
+		{"366; 12000 H.E.", time.Date(2000, time.December, 31, 0, 0, 0, 0, time.Local), true},
+		// Parsers fill pages without dates with day zero.
+		{"0; 12000 H.E.", time.Time{}, false},
+		{"", time.Time{}, false},
+		{"yesterday", time.Time{}, false},
+	}
+	for _, test := range tests {
+		page := yunyun.NewPage()
+		page.Date, page.Updated = test.date, test.date
+		date, found := PageDate(page)
+		if found != test.found || (found && !date.Equal(test.want)) {
+			t.Errorf("PageDate(%q) = %v, %t, want %v, %t", test.date, date, found, test.want, test.found)
+		}
+		if updated, found := PageUpdated(page); found != test.found || (found && !updated.Equal(test.want)) {
+			t.Errorf("PageUpdated(%q) = %v, %t, want %v, %t", test.date, updated, found, test.want, test.found)
+		}
+	}
+}
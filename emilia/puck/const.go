@@ -13,6 +13,10 @@ const (
 	ExtensionMarkdown = ".md"
 	// ExtensionHtml is the extension of html files.
 	ExtensionHtml = ".html"
+	// ExtensionGemini is the extension of gemtext files.
+	ExtensionGemini = ".gmi"
+	// ExtensionJson is the extension of json files.
+	ExtensionJson = ".json"
 
 	// DefaultPreviewFile is the name of the file where the preview of the gallery is stored.
 	DefaultPreviewFile = "preview.png"
@@ -22,6 +26,32 @@ const (
 	// DefaultPreviewDirectory is the name of the dir where all gallery previews are stored.
 	DefaultPreviewDirectory yunyun.RelativePathDir = "darkness_gallery_previews"
 
+	// DefaultTemplatesDirectory is the name of the dir with the custom templates.
+	DefaultTemplatesDirectory yunyun.RelativePathDir = "templates"
+	// DefaultSitemapFilename is the name of the sitemap if none is given.
+	DefaultSitemapFilename yunyun.RelativePathFile = "sitemap.xml"
+	// RobotsFilename is where crawlers look for the rules.
+	RobotsFilename yunyun.RelativePathFile = "robots.txt"
+	// DefaultSearchFilename is the name of the search index if none is given.
+	DefaultSearchFilename yunyun.RelativePathFile = "search.json"
+	// DefaultSearchWidgetFilename is the name of the search widget if none is given.
+	DefaultSearchWidgetFilename yunyun.RelativePathFile = "search.js"
+	// DefaultTagsDirectory is the name of the dir with the tag pages if none is given.
+	DefaultTagsDirectory yunyun.RelativePathDir = "tags"
+	// DefaultTagsTitle is the title of the tag index if none is given.
+	DefaultTagsTitle = "Tags"
+	// DefaultArchiveDirectory is the name of the dir with the archive pages if none is given.
+	DefaultArchiveDirectory yunyun.RelativePathDir = "archive"
+	// DefaultArchiveTitle is the title of the archive if none is given.
+	DefaultArchiveTitle = "Archive"
+	// DefaultGraphFilename is the name of the link graph if none is given.
+	DefaultGraphFilename yunyun.RelativePathFile = "graph.json"
+	// DefaultLanguage is the language of the pages if none is given.
+	DefaultLanguage = "en"
+
+	// LiveReloadEndpoint is where the serving browsers listen for reloads.
+	LiveReloadEndpoint = "/_darkness/reload"
+
 	PagePreviewWidth  = 1200
 	PagePreviewHeight = 700
 )
@@ -29,4 +59,11 @@ const (
 var (
 	PagePreviewWidthString  = strconv.Itoa(PagePreviewWidth)
 	PagePreviewHeightString = strconv.Itoa(PagePreviewHeight)
+
+	// DefaultIgnoredAssets are the patterns of the files that belong to the
+	// project rather than the site, so they're never copied to the output.
+	DefaultIgnoredAssets = []string{
+		"README*", "LICENSE*", "COPYING*", "CHANGELOG*", "Makefile", "Dockerfile",
+		"go.mod", "go.sum", "*.go", "*.sh", "package.json", "package-lock.json",
+	}
 )
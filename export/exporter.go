@@ -1,12 +1,15 @@
 package export
 
 import (
+	"fmt"
 	"io"
 	"log"
 
 	"github.com/thecsw/darkness/emilia/alpha"
 	"github.com/thecsw/darkness/emilia/puck"
+	"github.com/thecsw/darkness/export/gemini"
 	"github.com/thecsw/darkness/export/html"
+	"github.com/thecsw/darkness/export/json"
 	"github.com/thecsw/darkness/yunyun"
 )
 
@@ -16,14 +19,40 @@ type Exporter interface {
 	Do(*yunyun.Page) io.Reader
 }
 
-// BuildExporter builds the exporter based on the config.
-func BuildExporter(conf *alpha.DarknessConfig) Exporter {
-	var exporter Exporter
-	switch conf.Project.Output {
-	case puck.ExtensionHtml: // html
-		exporter = html.ExporterHtml{Config: conf}
-	default: // unknown
-		log.Fatalf("unknown output type: %s", conf.Project.Output)
+// registry maps output extensions to the functions that build their exporters.
+var registry = map[string]func(*alpha.DarknessConfig) (Exporter, error){
+	puck.ExtensionHtml: func(conf *alpha.DarknessConfig) (Exporter, error) {
+		templates, err := html.LoadTemplates(conf)
+		if err != nil {
+			return nil, err
+		}
+		return html.ExporterHtml{Config: conf, Templates: templates}, nil
+	},
+	puck.ExtensionGemini: func(conf *alpha.DarknessConfig) (Exporter, error) { return gemini.ExporterGemini{Config: conf}, nil },
+	puck.ExtensionJson:   func(conf *alpha.DarknessConfig) (Exporter, error) { return json.ExporterJson{Config: conf}, nil },
+}
+
+// BuildExporter builds the exporter for the given output extension.
+func BuildExporter(conf *alpha.DarknessConfig, ext string) (Exporter, error) {
+	builder, ok := registry[ext]
+	if !ok {
+		log.Fatalf("unknown output type: %s", ext)
+	}
+	return builder(conf)
+}
+
+// Exporters holds an exporter for each of the project's output extensions.
+type Exporters map[string]Exporter
+
+// BuildExporters builds exporters for all the output extensions in the config.
+func BuildExporters(conf *alpha.DarknessConfig) (Exporters, error) {
+	exporters := make(Exporters, len(conf.Project.Outputs))
+	for _, output := range conf.Project.Outputs {
+		exporter, err := BuildExporter(conf, output)
+		if err != nil {
+			return nil, fmt.Errorf("building %s exporter: %w", output, err)
+		}
+		exporters[output] = exporter
 	}
-	return exporter
+	return exporters, nil
 }
@@ -0,0 +1,190 @@
+package gemini
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/thecsw/darkness/emilia/puck"
+	"github.com/thecsw/darkness/emilia/rem"
+	"github.com/thecsw/darkness/yunyun"
+	"github.com/thecsw/gana"
+)
+
+const (
+	// preformattedToggle opens and closes preformatted blocks.
+	preformattedToggle = "```"
+	// linkPrefix starts a link line.
+	linkPrefix = "=> "
+	// listPrefix starts a list item.
+	listPrefix = "* "
+	// quotePrefix starts a quote line.
+	quotePrefix = "> "
+	// maxHeadingLevel is the deepest heading gemtext has.
+	maxHeadingLevel = 3
+)
+
+// Do exports the page into gemtext.
+func (e ExporterGemini) Do(page *yunyun.Page) io.Reader {
+	s := &state{conf: e.Config, page: page}
+	s.contentFunctions = []func(*yunyun.Content) string{
+		s.heading,
+		s.paragraph,
+		s.list,
+		s.listNumbered,
+		s.link,
+		s.sourceCode,
+		s.rawHtml,
+		s.horizontalLine,
+		s.attentionBlock,
+		s.table,
+		s.details,
+	}
+	return s.export()
+}
+
+// export runs the process of exporting.
+func (e *state) export() io.Reader {
+	defer puck.Stopwatch("Exported", "page", e.page.File).Record()
+
+	blocks := make([]string, 0, len(e.page.Contents)+2)
+	blocks = append(blocks, "# "+flattenText(e.page.Title))
+	if len(e.page.Date) > 0 {
+		blocks = append(blocks, e.page.Date)
+	}
+	for _, content := range e.page.Contents {
+		if built := e.contentFunctions[content.Type](content); len(built) > 0 {
+			blocks = append(blocks, built)
+		}
+	}
+	if footnotes := e.footnotes(); len(footnotes) > 0 {
+		blocks = append(blocks, footnotes)
+	}
+	return strings.NewReader(strings.Join(blocks, "\n\n") + "\n")
+}
+
+// heading gives us a heading gemtext representation.
+func (e *state) heading(content *yunyun.Content) string {
+	level := gana.Min(int(content.HeadingLevel), maxHeadingLevel)
+	return strings.Repeat("#", level) + " " + flattenText(content.Heading)
+}
+
+// paragraph gives us a paragraph gemtext representation, links can't be
+// inline, so they follow the paragraph as link lines.
+func (e *state) paragraph(content *yunyun.Content) string {
+	text := flattenText(content.Paragraph)
+	if content.IsQuote() {
+		text = quotePrefix + text
+	}
+	return withLinkLines(text, content.Paragraph)
+}
+
+// list gives us a list gemtext representation, galleries become link lines.
+func (e *state) list(content *yunyun.Content) string {
+	if content.IsGallery() {
+		return e.gallery(content)
+	}
+	return withLinkLines(strings.Join(gana.Map(func(item yunyun.ListItem) string {
+		return listPrefix + flattenText(item.Text)
+	}, content.List), "\n"), listText(content))
+}
+
+// listNumbered gives us a numbered list gemtext representation.
+func (e *state) listNumbered(content *yunyun.Content) string {
+	items := make([]string, len(content.List))
+	for i, item := range content.List {
+		items[i] = fmt.Sprintf("%d. %s", i+1, flattenText(item.Text))
+	}
+	return withLinkLines(strings.Join(items, "\n"), listText(content))
+}
+
+// gallery gives us a link line for each of the gallery images.
+func (e *state) gallery(content *yunyun.Content) string {
+	return strings.Join(gana.Map(func(item yunyun.ListItem) string {
+		galleryItem := rem.NewGalleryItem(e.page, content, item.Text)
+		image, _ := rem.GalleryImage(e.conf, galleryItem)
+		return linkLine(string(image), galleryItem.Text)
+	}, content.List), "\n")
+}
+
+// link gives us a link line.
+func (e *state) link(content *yunyun.Content) string {
+	text := content.LinkTitle
+	if len(content.Caption) > 0 {
+		text = content.Caption
+	}
+	return linkLine(content.Link, flattenText(text))
+}
+
+// sourceCode gives us a preformatted block.
+func (e *state) sourceCode(content *yunyun.Content) string {
+	return preformattedToggle + content.SourceCodeLang + "\n" + content.SourceCode + "\n" + preformattedToggle
+}
+
+// rawHtml is skipped, as gemini clients can't show html.
+func (e *state) rawHtml(content *yunyun.Content) string {
+	return ""
+}
+
+// horizontalLine gives us a separator line.
+func (e *state) horizontalLine(content *yunyun.Content) string {
+	return "---"
+}
+
+// attentionBlock gives us an attention block as a quote.
+func (e *state) attentionBlock(content *yunyun.Content) string {
+	return withLinkLines(quotePrefix+flattenText(content.AttentionTitle)+": "+flattenText(content.AttentionText), content.AttentionText)
+}
+
+// table gives us the table as an aligned preformatted block.
+func (e *state) table(content *yunyun.Content) string {
+	widths := make([]int, 0, 4)
+	rows := make([][]string, len(content.Table))
+	for i, row := range content.Table {
+		rows[i] = gana.Map(flattenText, row)
+		for j, cell := range rows[i] {
+			if j >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[j] = gana.Max(widths[j], len([]rune(cell)))
+		}
+	}
+	lines := make([]string, 0, len(rows)+3)
+	for i, row := range rows {
+		cells := make([]string, len(row))
+		for j, cell := range row {
+			cells[j] = cell + strings.Repeat(" ", widths[j]-len([]rune(cell)))
+		}
+		lines = append(lines, strings.TrimRight(strings.Join(cells, " | "), " "))
+		if i == 0 && content.TableHeaders {
+			lines = append(lines, strings.Join(gana.Map(func(width int) string {
+				return strings.Repeat("-", width)
+			}, widths[:len(row)]), "-+-"))
+		}
+	}
+	table := preformattedToggle + "\n" + strings.Join(lines, "\n") + "\n" + preformattedToggle
+	if len(content.Caption) > 0 {
+		table += "\n" + flattenText(content.Caption)
+	}
+	return table
+}
+
+// details gives us the summary as a heading, gemini has no folding.
+func (e *state) details(content *yunyun.Content) string {
+	if content.IsDetails() {
+		return strings.Repeat("#", maxHeadingLevel) + " " + flattenText(content.Summary)
+	}
+	return ""
+}
+
+// footnotes gives us the footnotes section.
+func (e *state) footnotes() string {
+	if len(e.page.Footnotes) < 1 {
+		return ""
+	}
+	footnotes := make([]string, len(e.page.Footnotes))
+	for i, footnote := range e.page.Footnotes {
+		footnotes[i] = withLinkLines(footnoteLabel(i+1)+" "+flattenText(footnote), footnote)
+	}
+	return "## Footnotes\n\n" + strings.Join(footnotes, "\n")
+}
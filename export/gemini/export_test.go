@@ -0,0 +1,83 @@
+package gemini
+
+import (
+	"io"
+	"testing"
+
+	"github.com/thecsw/darkness/internal/testutil"
+	"github.com/thecsw/darkness/yunyun"
+)
+
+// export exports a page with the contents into gemtext.
+func export(t *testing.T, contents ...*yunyun.Content) string {
+	t.Helper()
+	conf := testutil.Project(t, nil)
+	yunyun.ActiveMarkings.BuildRegex()
+	page := yunyun.NewPage(
+		yunyun.WithFilename("index.org"),
+		yunyun.WithLocation("."),
+		yunyun.WithContents(contents),
+	)
+	page.Title, page.Date = "Home", "7; 12024 H.E."
+	data, err := io.ReadAll(ExporterGemini{Config: conf}.Do(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestDo(t *testing.T) {
+	tests := []struct {
+		name    string
+		content *yunyun.Content
+		want    string
+	}{
+		{
+			name:    "heading",
+			content: &yunyun.Content{Type: yunyun.TypeHeading, HeadingLevel: 2, Heading: "About *me*"},
+			want:    "## About me",
+		},
+		{
+			name:    "deep heading",
+			content: &yunyun.Content{Type: yunyun.TypeHeading, HeadingLevel: 5, Heading: "Deep"},
+			want:    "### Deep",
+		},
+		{
+			name:    "link",
+			content: &yunyun.Content{Type: yunyun.TypeLink, Link: "https://example.org", LinkTitle: "Example"},
+			want:    "=> https://example.org Example",
+		},
+		{
+			name:    "inline link",
+			content: &yunyun.Content{Type: yunyun.TypeParagraph, Paragraph: "Read [[https://example.org][this]] now."},
+			want:    "Read this now.\n=> https://example.org this",
+		},
+		{
+			name: "list",
+			content: &yunyun.Content{Type: yunyun.TypeList, List: []yunyun.ListItem{
+				{Text: "one"}, {Text: "[[https://example.org][two]]"},
+			}},
+			want: "* one\n* two\n=> https://example.org two",
+		},
+		{
+			name: "numbered list",
+			content: &yunyun.Content{Type: yunyun.TypeListNumbered, List: []yunyun.ListItem{
+				{Text: "first"}, {Text: "second"},
+			}},
+			want: "1. first\n2. second",
+		},
+		{
+			name:    "preformatted",
+			content: &yunyun.Content{Type: yunyun.TypeSourceCode, SourceCodeLang: "go", SourceCode: "#include\n* not a list"},
+			want:    "```go\n#include\n* not a list\n```",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			want := "# Home\n\n7; 12024 H.E.\n\n" + test.want + "\n"
+			if got := export(t, test.content); got != want {
+				t.Errorf("got %q, want %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package gemini
+
+import (
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/yunyun"
+)
+
+// ExporterGemini is the exporter for gemini (gemtext).
+type ExporterGemini struct {
+	// Config is the configuration for the exporter.
+	Config *alpha.DarknessConfig
+}
+
+// state is the state of the exporter.
+type state struct {
+	// page is the source data that will be used for gemtext building.
+	page *yunyun.Page
+	// contentFunctions is dictionary of rules to execute on content types.
+	contentFunctions []func(*yunyun.Content) string
+	// conf is the configuration for the exporter.
+	conf *alpha.DarknessConfig
+}
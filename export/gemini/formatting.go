@@ -0,0 +1,49 @@
+package gemini
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/thecsw/darkness/emilia/narumi"
+	"github.com/thecsw/darkness/yunyun"
+	"github.com/thecsw/gana"
+)
+
+// flattenText returns the plain text that gemtext can show.
+func flattenText(text string) string {
+	text = yunyun.RemoveFormatting(yunyun.FancyText(text))
+	text = yunyun.FootnotePostProcessingRegexp.ReplaceAllStringFunc(text, func(what string) string {
+		num, _ := strconv.Atoi(strings.Trim(what, "!"))
+		return footnoteLabel(num)
+	})
+	return strings.TrimSpace(strings.ReplaceAll(text, "\n", " "))
+}
+
+// footnoteLabel returns the label of the footnote as in the html exporter.
+func footnoteLabel(num int) string {
+	return "[" + narumi.FootnoteLabeler(num) + "]"
+}
+
+// linkLine returns a gemtext link line.
+func linkLine(link, text string) string {
+	if len(text) < 1 || text == link {
+		return linkPrefix + link
+	}
+	return linkPrefix + link + " " + text
+}
+
+// withLinkLines adds link lines for all the links found in source after text.
+func withLinkLines(text, source string) string {
+	links := yunyun.ExtractLinks(source)
+	if len(links) < 1 {
+		return text
+	}
+	return text + "\n" + strings.Join(gana.Map(func(link *yunyun.ExtractedLink) string {
+		return linkLine(link.Link, flattenText(link.Text))
+	}, links), "\n")
+}
+
+// listText joins the list items, so their links can be extracted.
+func listText(content *yunyun.Content) string {
+	return strings.Join(gana.Map(func(item yunyun.ListItem) string { return item.Text }, content.List), "\n")
+}
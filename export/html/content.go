@@ -76,6 +76,9 @@ func paragraphClass(content *yunyun.Content) string {
 	if content.IsDropCap() {
 		return " dropcap"
 	}
+	if content.IsPagination() {
+		return " pagination"
+	}
 	return ""
 }
 
@@ -138,13 +141,8 @@ func (e *state) sourceCode(content *yunyun.Content) string {
 		content.CustomHtmlTags,
 		narumi.MapSourceCodeLang(content.SourceCodeLang),
 		content.SourceCodeLang,
-		func() string {
-			// Remove the nested parser blockers
-			s := strings.ReplaceAll(content.SourceCode, ",#", "#")
-			// Escape the whatever HTML that is found in source code
-			s = html.EscapeString(s)
-			return s
-		}(),
+		// Escape the whatever HTML that is found in source code
+		html.EscapeString(content.SourceCode),
 	)
 }
 
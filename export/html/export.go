@@ -3,6 +3,7 @@ package html
 import (
 	_ "embed"
 	"fmt"
+	"html/template"
 	"io"
 	"regexp"
 	"strconv"
@@ -26,7 +27,23 @@ var (
 )
 
 func (e ExporterHtml) Do(page *yunyun.Page) io.Reader {
-	s := &state{conf: e.Config, page: page}
+	return e.newState(page).export()
+}
+
+// Body exports only the contents and the footnotes of the page, without
+// the rest of the document, which is useful for embedding pages elsewhere.
+func (e ExporterHtml) Body(page *yunyun.Page) string {
+	s := e.newState(page)
+	return s.body() + s.addFootnotes()
+}
+
+// newState creates the exporting state of the page.
+func (e ExporterHtml) newState(page *yunyun.Page) *state {
+	// Other exporters might be working on the same page.
+	s := &state{conf: e.Config, page: page.Clone(), templates: e.Templates}
+	if s.templates == nil {
+		s.templates = defaultTemplates
+	}
 	s.contentFunctions = []func(*yunyun.Content) string{
 		s.heading,
 		s.paragraph,
@@ -40,13 +57,35 @@ func (e ExporterHtml) Do(page *yunyun.Page) io.Reader {
 		s.table,
 		s.details,
 	}
-	return s.export()
+	return s
 }
 
 // Export runs the process of exporting
 func (e *state) export() io.Reader {
 	defer puck.Stopwatch("Exported", "page", e.page.File).Record()
 
+	// If the page hasn't set a custom preview, default to emilia.
+	if len(e.page.Accoutrement.Preview) < 1 {
+		e.page.Accoutrement.Preview = string(e.conf.Website.Preview)
+	}
+
+	if e.page.Accoutrement.PreviewGenerate.IsEnabled() {
+		e.page.Accoutrement.PreviewWidth = puck.PagePreviewWidthString
+		e.page.Accoutrement.PreviewHeight = puck.PagePreviewHeightString
+		akane.RequestPagePreview(e.page.Location, e.page.Title, e.page.Date)
+	}
+
+	content := e.body()
+
+	output := &strings.Builder{}
+	if err := e.templates.ExecuteTemplate(output, pageTemplate, e.document(content)); err != nil {
+		puck.Logger.Error("Rendering page template", "page", e.page.File, "err", err)
+	}
+	return strings.NewReader(output.String())
+}
+
+// body returns the HTML representation of the page's contents.
+func (e *state) body() string {
 	// Initialize the html mapping after yunyun built regexes.
 	markupHtmlMappingSetOnce.Do(func() {
 		markupHtmlMapping = map[*regexp.Regexp]string{
@@ -65,21 +104,11 @@ func (e *state) export() io.Reader {
 	if e.page.Accoutrement.Tomb.IsEnabled() {
 		e.addTomb()
 	}
-	// If the page hasn't set a custom preview, default to emilia.
-	if len(e.page.Accoutrement.Preview) < 1 {
-		e.page.Accoutrement.Preview = string(e.conf.Website.Preview)
-	}
 
 	if e.page.Accoutrement.Toc.IsEnabled() {
 		e.page.Contents = append(e.toc(), e.page.Contents...)
 	}
 
-	if e.page.Accoutrement.PreviewGenerate.IsEnabled() {
-		e.page.Accoutrement.PreviewWidth = puck.PagePreviewWidthString
-		e.page.Accoutrement.PreviewHeight = puck.PagePreviewHeightString
-		akane.RequestPagePreview(e.page.Location, e.page.Title, e.page.Date)
-	}
-
 	// Build the HTML (string) representation of each content
 	content := make([]string, 0, len(e.page.Contents))
 	for i, v := range e.page.Contents {
@@ -87,28 +116,28 @@ func (e *state) export() io.Reader {
 		e.currentContent = v
 		content = append(content, e.buildContent(v))
 	}
+	return strings.Join(content, "")
+}
 
-	output := fmt.Sprintf(`%s<!DOCTYPE html>
-<html lang="en">
-<head>
-%s
-<title>%s</title>
-</head>
-<body class="article">
-%s
-%s
-%s
-</body>
-</html>`,
-		darknessBanner,
-		e.combineAndFilterHtmlHead(),
-		processTitle(flattenFormatting(e.page.Title)),
-		e.authorHeader(),
-		strings.Join(content, ""),
-		e.addFootnotes(),
-	)
-
-	return strings.NewReader(output)
+// document returns the data that the templates use to render the page.
+func (e *state) document(content string) *Document {
+	return &Document{
+		Page:        e.page,
+		Config:      e.conf,
+		Banner:      template.HTML(darknessBanner),
+		Head:        template.HTML(e.combineAndFilterHtmlHead()),
+		Title:       template.HTML(processTitle(flattenFormatting(e.page.Title))),
+		Heading:     template.HTML(processTitle(e.page.Title)),
+		AuthorImage: e.authorImage(),
+		Navigation:  e.navigation(),
+		Content:     template.HTML(content),
+		Footnotes:   template.HTML(e.addFootnotes()),
+		Previous:    e.neighbour(e.page.Previous),
+		Next:        e.neighbour(e.page.Next),
+		Backlinks:   e.backlinks(),
+		Lang:        e.conf.Website.Language,
+		BodyClass:   defaultBodyClass,
+	}
 }
 
 // buildContent builds the HTML representation of a content.
@@ -162,47 +191,31 @@ var defaultScripts = []string{
 	`<script async src="https://sandyuraz.com/scripts/time.js"></script>`,
 }
 
+// liveReloadScript reloads the page when the server says so, stylesheets
+// are swapped in place without reloading the whole page.
+var liveReloadScript = `<script>(() => {
+const source = new EventSource("` + puck.LiveReloadEndpoint + `");
+source.addEventListener("reload", () => location.reload());
+source.addEventListener("css", () => document.querySelectorAll('link[rel="stylesheet"]').forEach((link) => {
+const url = new URL(link.href);
+if (url.origin !== location.origin) return;
+url.searchParams.set("darkness", Date.now());
+link.href = url.href;
+}));
+})();</script>`
+
 // scriptTags returns the script tags.
 func (e *state) scriptTags() []string {
-	return append(defaultScripts, e.page.Scripts...)
-}
-
-func (e *state) rssLink() string {
-	if !e.conf.RSS.Enable {
-		return ""
+	scripts := append(defaultScripts, e.page.Scripts...)
+	if e.conf.Runtime.LiveReload {
+		scripts = append(scripts, liveReloadScript)
 	}
-	return `<span><a href="/feed.xml" class="rss-link"><img src="/assets/rss.svg" class="rss-icon"></a></span><br>` + "\n"
+	return scripts
 }
 
-func (e *state) authorName() string {
-	if !e.conf.Author.NameEnable {
-		return ""
-	}
-	return `<span id="author" class="author">` + e.conf.Author.Name + `</span><br>` + "\n"
-}
-
-func (e *state) authorEmail() string {
-	if !e.conf.Author.EmailEnable {
-		return ""
-	}
-	return `<span id="email" class="email">` + e.conf.Author.Email + `</span><br>` + "\n"
-}
-
-// authorHeader returns the author header.
-func (e *state) authorHeader() string {
-	content := fmt.Sprintf(`
-<div class="header">
-<h1 class="section-1">%s%s</h1>
-<div class="menu">
-%s%s%s`,
-		e.authorImage(), processTitle(e.page.Title),
-		e.rssLink(), e.authorName(), e.authorEmail(),
-	)
-	content += `<span id="revdate">` + "\n"
-
-	// Build the navigation links.
-	navLinks := make([]string, 0, len(e.conf.Navigation))
-
+// navigation returns the header's navigation links.
+func (e *state) navigation() []NavigationLink {
+	links := make([]NavigationLink, 0, len(e.conf.Navigation))
 	// Go through elements.
 	for i := 1; i <= len(e.conf.Navigation); i++ {
 		// Get the navigation element read from Darkness' toml.
@@ -211,31 +224,41 @@ func (e *state) authorHeader() string {
 		if e.page.Location == v.Hide {
 			continue
 		}
-		// Build each of the navlinks and concat the hrefs.
-		navLinks = append(navLinks, fmt.Sprintf(`<a href="%s">%s</a>`,
-			e.conf.Runtime.Join(yunyun.RelativePathFile(v.Link)),
-			v.Title))
+		links = append(links, NavigationLink{
+			Link:  string(e.conf.Runtime.Join(yunyun.RelativePathFile(v.Link))),
+			Title: v.Title,
+		})
 	}
+	return links
+}
 
-	// Close the navigation links span.
-	content += strings.Join(navLinks, " | ") + `</span>`
+// neighbour returns the link to the page's neighbour, nil if there is none.
+func (e *state) neighbour(neighbour *yunyun.Neighbour) *NavigationLink {
+	if neighbour == nil {
+		return nil
+	}
+	return &NavigationLink{
+		Link:  string(e.conf.Runtime.Join(yunyun.RelativePathFile(neighbour.Location))),
+		Title: flattenFormatting(neighbour.Title),
+	}
+}
 
-	// Add the Holoscene time element.
-	content += `
-</div>
-<div id="hetime" class="menu"></div>
-</div>`
-	// Return the website header.
-	return content
+// backlinks returns the links to the pages linking to the page.
+func (e *state) backlinks() []NavigationLink {
+	links := make([]NavigationLink, 0, len(e.page.Backlinks))
+	for i := range e.page.Backlinks {
+		links = append(links, *e.neighbour(&e.page.Backlinks[i]))
+	}
+	return links
 }
 
-// authorHeader returns img element if author header image is given.
+// authorImage returns the author image link if it should be shown.
 func (e *state) authorImage() string {
 	// Return nothing if it's not provided.
 	if e.conf.Author.Image == "" || e.page.Accoutrement.AuthorImage.IsDisabled() {
 		return ""
 	}
-	return fmt.Sprintf(`<img id="myface" src="%s" alt="avatar">`, e.conf.Author.ImagePreComputed)
+	return string(e.conf.Author.ImagePreComputed)
 }
 
 // addTomb adds the tomb to the last paragraph.
@@ -246,8 +269,8 @@ func (e *state) addTomb() {
 	}
 	// Find the last paragraph and attached the tomb.
 	for i := len(e.page.Contents) - 1; i >= 0; i-- {
-		// Skip if it's not a paragraph.
-		if !e.page.Contents[i].IsParagraph() {
+		// Skip if it's not a paragraph, the pagination is not a part of the text.
+		if !e.page.Contents[i].IsParagraph() || e.page.Contents[i].IsPagination() {
 			continue
 		}
 		// Add the tomb and break out.
@@ -1,6 +1,8 @@
 package html
 
 import (
+	"html/template"
+
 	"github.com/thecsw/darkness/emilia/alpha"
 	"github.com/thecsw/darkness/yunyun"
 )
@@ -9,6 +11,9 @@ import (
 type ExporterHtml struct {
 	// Config is the configuration for the exporter.
 	Config *alpha.DarknessConfig
+	// Templates are the templates to render pages with, defaults
+	// to the built-in ones if nil.
+	Templates *template.Template
 }
 
 // state is the state of the exporter.
@@ -29,4 +34,6 @@ type state struct {
 	inWriting bool
 	// conf is the configuration for the exporter.
 	conf *alpha.DarknessConfig
+	// templates are the templates to render the page with.
+	templates *template.Template
 }
@@ -27,7 +27,7 @@ func markupHtml(text string) string {
 	text = yunyun.BoldText.ReplaceAllString(text, markupHtmlMapping[yunyun.BoldText])
 	text = yunyun.KeyboardRegexp.ReplaceAllString(text, `<kbd>$1</kbd>`)
 	text = yunyun.NewLineRegexp.ReplaceAllString(text, `$1<br>`)
-	return text
+	return yunyun.RemoveLiteralGuards(text)
 }
 
 // processText returns a properly formatted HTML of a text
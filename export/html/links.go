@@ -14,18 +14,33 @@ type rel struct {
 	Type string
 }
 
-// linkTag returns a string of the form <link rel="..." href="..." />
+// linkTag returns a string of the form <link rel="..." href="..." type="..."/>,
+// the type is left out if it's empty.
 func linkTag(val rel) string {
+	if len(val.Type) < 1 {
+		return fmt.Sprintf(`<link rel="%s" href="%s"/>`, val.Rel, val.Href)
+	}
 	return fmt.Sprintf(`<link rel="%s" href="%s" type="%s"/>`, val.Rel, val.Href, val.Type)
 }
 
 // linkTags returns a string of the form <link rel="..." href="..." /> for an entire page
 func (e *state) linkTags() []string {
-	return gana.Map(linkTag, []rel{
+	rels := []rel{
 		{"canonical", e.conf.Runtime.Join(yunyun.RelativePathFile(e.page.Location)), ""},
 		{"shortcut icon", e.conf.Runtime.Join("assets/favicon.ico"), "image/x-icon"},
 		{"apple-touch-icon", e.conf.Runtime.Join("assets/apple-touch-icon.png"), "image/png"},
 		{"image_src", e.conf.Runtime.Join("assets/android-chrome-512x512.png"), "image/png"},
 		{"icon", e.conf.Runtime.Join("assets/favicon.ico"), ""},
-	})
+	}
+	// Pages can bring their own links, like the pagination's prev and next.
+	for _, link := range e.page.Links {
+		rels = append(rels, rel{link.Rel, yunyun.FullPathFile(link.Href), link.Type})
+	}
+	if e.page.Previous != nil {
+		rels = append(rels, rel{"prev", e.conf.Runtime.Join(yunyun.RelativePathFile(e.page.Previous.Location)), ""})
+	}
+	if e.page.Next != nil {
+		rels = append(rels, rel{"next", e.conf.Runtime.Join(yunyun.RelativePathFile(e.page.Next.Location)), ""})
+	}
+	return gana.Map(linkTag, rels)
 }
@@ -65,14 +65,19 @@ var metaTopTag = []string{
 
 // addBasic adds the basic meta tags
 func addBasic(conf *alpha.DarknessConfig, page *yunyun.Page, description string) []string {
-	return append(metaTopTag, gana.Map(metaTag, []meta{
+	basic := []meta{
 		{"viewport", "viewport", "width=device-width, initial-scale=1.0"},
 		{"generator", "generator", "Darkness"},
 		{"author", "author", conf.Author.Name},
 		{"date", "date", page.Date},
 		{"theme-color", "theme-color", conf.Website.Color},
 		{"description", "description", html.EscapeString(description)},
-	})...)
+	}
+	// Only tagged pages have keywords.
+	if len(page.Tags) > 0 {
+		basic = append(basic, meta{"keywords", "keywords", html.EscapeString(strings.Join(page.Tags, ", "))})
+	}
+	return append(metaTopTag, gana.Map(metaTag, basic)...)
 }
 
 // addOpenGraph adds the opengraph preview meta tags
@@ -0,0 +1,91 @@
+package html
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"path/filepath"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/yunyun"
+)
+
+const (
+	// pageTemplate is the template that renders the whole document.
+	pageTemplate = "page.html"
+	// defaultBodyClass is the class of the body element.
+	defaultBodyClass = "article"
+)
+
+var (
+	//go:embed templates/*.html
+	defaultTemplatesSource embed.FS
+	// defaultTemplates are the built-in templates, with the functions that
+	// the project's templates can use, bound to the site by `LoadTemplates`.
+	defaultTemplates = template.Must(template.New(pageTemplate).Funcs(template.FuncMap{
+		"url": func(path string) string { return path },
+	}).ParseFS(defaultTemplatesSource, "templates/*.html"))
+)
+
+// Document is what the templates receive when rendering a page.
+type Document struct {
+	// Page is the page being rendered.
+	Page *yunyun.Page
+	// Config is the site's config.
+	Config *alpha.DarknessConfig
+
+	// Banner is the darkness banner comment.
+	Banner template.HTML
+	// Head is everything that goes in the head, besides the title.
+	Head template.HTML
+	// Title is the page's title for the title element.
+	Title template.HTML
+	// Heading is the page's title for the header.
+	Heading template.HTML
+	// AuthorImage is the author image link, if it should be shown.
+	AuthorImage string
+	// Navigation are the navigation links of the page.
+	Navigation []NavigationLink
+	// Content is the rendered content of the page.
+	Content template.HTML
+	// Footnotes are the rendered footnotes of the page.
+	Footnotes template.HTML
+	// Previous and Next are the page's neighbours, if it has any.
+	Previous, Next *NavigationLink
+	// Backlinks are the pages linking to the page.
+	Backlinks []NavigationLink
+
+	// Lang is the language of the document.
+	Lang string
+	// BodyClass is the class of the body element.
+	BodyClass string
+}
+
+// NavigationLink is a single link of the header's navigation.
+type NavigationLink struct {
+	// Link is the full link.
+	Link string
+	// Title is the text of the link.
+	Title string
+}
+
+// LoadTemplates returns the default templates overridden by the ones found
+// in the project's templates directory, which fail the build if they
+// don't parse, so a broken template never quietly builds a different site.
+func LoadTemplates(conf *alpha.DarknessConfig) (*template.Template, error) {
+	templates := template.Must(defaultTemplates.Clone()).Funcs(template.FuncMap{
+		"url": func(path string) string { return string(conf.Runtime.Join(yunyun.RelativePathFile(path))) },
+	})
+	dir := string(conf.Runtime.WorkDir.Join(yunyun.RelativePathFile(conf.Project.Templates)))
+	custom, err := filepath.Glob(filepath.Join(dir, "*.html"))
+	if err != nil {
+		return nil, fmt.Errorf("finding templates in %s: %w", dir, err)
+	}
+	if len(custom) < 1 {
+		return templates, nil
+	}
+	if _, err := templates.ParseFiles(custom...); err != nil {
+		return nil, fmt.Errorf("parsing templates in %s: %w", dir, err)
+	}
+	return templates, nil
+}
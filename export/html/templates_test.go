@@ -0,0 +1,67 @@
+package html
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/thecsw/darkness/internal/testutil"
+)
+
+// render renders an empty page with the templates of the project.
+func render(t *testing.T, files map[string]string) string {
+	t.Helper()
+	conf := testutil.Project(t, files)
+	templates, err := LoadTemplates(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := &strings.Builder{}
+	if err := templates.ExecuteTemplate(output, pageTemplate, Document{Config: conf, Content: "<p>Hello</p>"}); err != nil {
+		t.Fatal(err)
+	}
+	return output.String()
+}
+
+func TestLoadTemplatesDefault(t *testing.T) {
+	got := render(t, nil)
+	for _, want := range []string{"<!DOCTYPE html>", `<div class="header">`, "<p>Hello</p>"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("default page is missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestLoadTemplatesOverride(t *testing.T) {
+	got := render(t, map[string]string{
+		"templates/page.html": `<main>{{.Content}}</main><a href="{{url "about"}}">About</a>`,
+	})
+	want := `<main><p>Hello</p></main><a href="https://example.com/about">About</a>`
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestLoadTemplatesFallback(t *testing.T) {
+	// Only the header is overridden, the page and the footer stay.
+	got := render(t, map[string]string{
+		"templates/header.html": `<header>Custom</header>`,
+	})
+	if !strings.Contains(got, "<header>Custom</header>") {
+		t.Errorf("custom header is missing:\n%s", got)
+	}
+	if strings.Contains(got, `<div class="header">`) {
+		t.Errorf("default header is still there:\n%s", got)
+	}
+	if !strings.Contains(got, "<!DOCTYPE html>") || !strings.Contains(got, "<p>Hello</p>") {
+		t.Errorf("default page is missing:\n%s", got)
+	}
+}
+
+func TestLoadTemplatesBroken(t *testing.T) {
+	conf := testutil.Project(t, map[string]string{
+		"templates/page.html": `{{if .Content}}`,
+	})
+	if _, err := LoadTemplates(conf); err == nil {
+		t.Error("broken template loaded without an error")
+	}
+}
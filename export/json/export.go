@@ -0,0 +1,173 @@
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/thecsw/darkness/emilia/narumi"
+	"github.com/thecsw/darkness/emilia/puck"
+	"github.com/thecsw/darkness/yunyun"
+	"github.com/thecsw/gana"
+)
+
+// generator is the name of the program that exported the document.
+const generator = "darkness"
+
+// Do exports the page into JSON.
+func (e ExporterJson) Do(page *yunyun.Page) io.Reader {
+	s := &state{conf: e.Config, page: page}
+	return s.export()
+}
+
+// export runs the process of exporting.
+func (e *state) export() io.Reader {
+	defer puck.Stopwatch("Exported", "page", e.page.File).Record()
+
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	// Contents are full of html, which doesn't need escaping in JSON.
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(Document{
+		Schema:    SchemaVersion,
+		Generator: generator,
+		Page:      e.exportPage(),
+	}); err != nil {
+		puck.Logger.Error("Encoding to json", "page", e.page.File, "err", err)
+	}
+	return buf
+}
+
+// exportPage converts the page into its schema representation.
+func (e *state) exportPage() Page {
+	page := Page{
+		File:          string(e.page.File),
+		Location:      string(e.page.Location),
+		Url:           string(e.conf.Runtime.Join(yunyun.RelativePathFile(e.page.Location))),
+		Title:         yunyun.RemoveLiteralGuards(e.page.Title),
+		Author:        e.page.Author,
+		Date:          e.page.Date,
+		Updated:       e.page.Updated,
+		DateHoloscene: e.page.DateHoloscene,
+		Tags:          nonNil(e.page.Tags),
+		Links: gana.Map(func(link yunyun.Link) Link {
+			return Link{Rel: link.Rel, Type: link.Type, Href: link.Href}
+		}, e.page.Links),
+		Accoutrement: exportAccoutrement(e.page.Accoutrement),
+		Scripts:      nonNil(e.page.Scripts),
+		Stylesheets:  nonNil(e.page.Stylesheets),
+		HtmlHead:     nonNil(e.page.HtmlHead),
+		Footnotes:    nonNil(gana.Map(yunyun.RemoveLiteralGuards, e.page.Footnotes)),
+		Contents:     gana.Map(exportContent, e.page.Contents),
+	}
+	if e.page.Enclosure != nil {
+		page.Enclosure = &Enclosure{
+			Link:     e.page.Enclosure.Link,
+			Duration: e.page.Enclosure.Duration,
+			Episode:  e.page.Enclosure.Episode,
+			Explicit: e.page.Enclosure.Explicit != nil && *e.page.Enclosure.Explicit,
+		}
+	}
+	if e.page.Series != nil {
+		page.Series = &Series{Name: e.page.Series.Name, Order: e.page.Series.Order}
+	}
+	page.Previous = e.exportNeighbour(e.page.Previous)
+	page.Next = e.exportNeighbour(e.page.Next)
+	for i := range e.page.Backlinks {
+		page.Backlinks = append(page.Backlinks, *e.exportNeighbour(&e.page.Backlinks[i]))
+	}
+	// Same as the feeds, placeholder dates are not real dates.
+	if date, found := narumi.PageDate(e.page); found {
+		page.DateParsed = date.Format(time.RFC3339)
+	}
+	if updated, found := narumi.PageUpdated(e.page); found {
+		page.UpdatedParsed = updated.Format(time.RFC3339)
+	}
+	return page
+}
+
+// exportAccoutrement converts the accoutrement into its schema representation.
+func exportAccoutrement(a *yunyun.Accoutrement) Accoutrement {
+	if a == nil {
+		a = &yunyun.Accoutrement{}
+	}
+	return Accoutrement{
+		Preview:                 a.Preview,
+		PreviewWidth:            a.PreviewWidth,
+		PreviewHeight:           a.PreviewHeight,
+		PreviewGenerate:         accoutrementFlips[a.PreviewGenerate],
+		ExcludeHtmlHeadContains: nonNil(a.ExcludeHtmlHeadContains),
+		Draft:                   accoutrementFlips[a.Draft],
+		Tomb:                    accoutrementFlips[a.Tomb],
+		AuthorImage:             accoutrementFlips[a.AuthorImage],
+		Math:                    accoutrementFlips[a.Math],
+		Toc:                     accoutrementFlips[a.Toc],
+		Sitemap:                 accoutrementFlips[a.Sitemap],
+		SitemapPriority:         a.SitemapPriority,
+		SitemapChangefreq:       a.SitemapChangefreq,
+		AutoIndex:               accoutrementFlips[a.AutoIndex],
+	}
+}
+
+// exportNeighbour converts the neighbour into its schema representation.
+func (e *state) exportNeighbour(neighbour *yunyun.Neighbour) *Neighbour {
+	if neighbour == nil {
+		return nil
+	}
+	return &Neighbour{
+		Title:    neighbour.Title,
+		Location: string(neighbour.Location),
+		Url:      string(e.conf.Runtime.Join(yunyun.RelativePathFile(neighbour.Location))),
+	}
+}
+
+// exportContent converts the content into its schema representation, the
+// guards of literal text are only for our own parsers, so they're removed.
+func exportContent(c *yunyun.Content) Content {
+	flags := make([]string, 0, len(contentFlags))
+	for _, flag := range contentFlags {
+		if yunyun.HasFlag(&c.Options, flag.flag) {
+			flags = append(flags, flag.name)
+		}
+	}
+	return Content{
+		Type:                 contentTypes[c.Type],
+		Flags:                flags,
+		Heading:              yunyun.RemoveLiteralGuards(c.Heading),
+		HeadingLevel:         c.HeadingLevel,
+		HeadingLevelAdjusted: c.HeadingLevelAdjusted,
+		HeadingFirst:         c.HeadingFirst,
+		HeadingLast:          c.HeadingLast,
+		HeadingChild:         c.HeadingChild,
+		Paragraph:            yunyun.RemoveLiteralGuards(c.Paragraph),
+		List: gana.Map(func(item yunyun.ListItem) ListItem {
+			return ListItem{Level: item.Level, Text: yunyun.RemoveLiteralGuards(item.Text)}
+		}, c.List),
+		Link:                c.Link,
+		LinkTitle:           yunyun.RemoveLiteralGuards(c.LinkTitle),
+		LinkDescription:     yunyun.RemoveLiteralGuards(c.LinkDescription),
+		SourceCode:          c.SourceCode,
+		SourceCodeLang:      c.SourceCodeLang,
+		RawHtml:             c.RawHtml,
+		AttentionTitle:      yunyun.RemoveLiteralGuards(c.AttentionTitle),
+		AttentionText:       yunyun.RemoveLiteralGuards(c.AttentionText),
+		Table:               gana.Map(func(row []string) []string { return gana.Map(yunyun.RemoveLiteralGuards, row) }, c.Table),
+		TableHeaders:        c.TableHeaders,
+		Summary:             yunyun.RemoveLiteralGuards(c.Summary),
+		GalleryPath:         string(c.GalleryPath),
+		GalleryImagesPerRow: c.GalleryImagesPerRow,
+		Caption:             yunyun.RemoveLiteralGuards(c.Caption),
+		Attributes:          c.Attributes,
+		CustomHtmlTags:      c.CustomHtmlTags,
+	}
+}
+
+// nonNil makes sure empty lists are exported as `[]` and not `null`.
+func nonNil[T any](what []T) []T {
+	if what == nil {
+		return []T{}
+	}
+	return what
+}
@@ -0,0 +1,53 @@
+package json
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/thecsw/darkness/internal/testutil"
+	"github.com/thecsw/darkness/yunyun"
+)
+
+// update rewrites the golden files with the current output.
+var update = flag.Bool("update", false, "update the golden files")
+
+// TestDoGolden pins the schema, readers of the JSON break whenever it
+// changes without bumping `SchemaVersion`.
+func TestDoGolden(t *testing.T) {
+	conf := testutil.Project(t, nil)
+	page := yunyun.NewPage(
+		yunyun.WithFilename("blog/hello/index.org"),
+		yunyun.WithLocation("blog/hello"),
+		yunyun.WithContents([]*yunyun.Content{
+			{Type: yunyun.TypeHeading, Heading: "Hello", HeadingLevel: 2, HeadingFirst: true},
+			{Type: yunyun.TypeParagraph, Paragraph: "Some /quoted/ text.", Options: yunyun.InQuoteFlag | yunyun.InCenterFlag},
+			{Type: yunyun.TypeListNumbered, List: []yunyun.ListItem{{Level: 1, Text: "one"}, {Level: 1, Text: "two"}}},
+			{Type: yunyun.TypeSourceCode, SourceCode: "fmt.Println()", SourceCodeLang: "go"},
+		}),
+	)
+	page.Title = "Hello"
+	page.Date = "7; 12024 H.E."
+	page.Tags = []string{"greetings"}
+	page.Accoutrement.Toc = yunyun.AccoutrementEnabled
+
+	data, err := io.ReadAll(ExporterJson{Config: conf}.Do(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden := filepath.Join("testdata", "page.json")
+	if *update {
+		if err := os.WriteFile(golden, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != string(want) {
+		t.Errorf("got:\n%s\nwant:\n%s", data, want)
+	}
+}
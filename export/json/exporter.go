@@ -0,0 +1,21 @@
+package json
+
+import (
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/yunyun"
+)
+
+// ExporterJson is the exporter for the JSON representation of pages,
+// which is meant to be consumed by other tools.
+type ExporterJson struct {
+	// Config is the configuration for the exporter.
+	Config *alpha.DarknessConfig
+}
+
+// state is the state of the exporter.
+type state struct {
+	// page is the source data that will be used for JSON building.
+	page *yunyun.Page
+	// conf is the configuration for the exporter.
+	conf *alpha.DarknessConfig
+}
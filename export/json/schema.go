@@ -0,0 +1,204 @@
+package json
+
+import "github.com/thecsw/darkness/yunyun"
+
+// SchemaVersion is the version of the JSON schema, it's bumped
+// whenever a field is renamed, removed, or changes its meaning.
+// New fields can be added without bumping the version.
+const SchemaVersion = 1
+
+// Document is the top level object of every exported page.
+type Document struct {
+	// Schema is the `SchemaVersion` the document was exported with.
+	Schema int `json:"schema"`
+	// Generator is always "darkness".
+	Generator string `json:"generator"`
+	// Page is the exported page.
+	Page Page `json:"page"`
+}
+
+// Page is the exported `yunyun.Page`.
+type Page struct {
+	// File is the source file of the page, relative to the project.
+	File string `json:"file"`
+	// Location is the directory of the page, relative to the project.
+	Location string `json:"location"`
+	// Url is the full url of the page.
+	Url string `json:"url"`
+	// Title is the title of the page.
+	Title string `json:"title"`
+	// Author is the author of the page.
+	Author string `json:"author,omitempty"`
+	// Date is the date of the page as written.
+	Date string `json:"date,omitempty"`
+	// DateParsed is the date of the page in RFC 3339, if it could be parsed.
+	DateParsed string `json:"date_parsed,omitempty"`
+	// Updated is the date the page was last updated as written.
+	Updated string `json:"updated,omitempty"`
+	// UpdatedParsed is the date the page was last updated in RFC 3339, if it could be parsed.
+	UpdatedParsed string `json:"updated_parsed,omitempty"`
+	// DateHoloscene tells us whether the first paragraph is a holoscene date.
+	DateHoloscene bool `json:"date_holoscene"`
+	// Enclosure is the media file attached to the page.
+	Enclosure *Enclosure `json:"enclosure,omitempty"`
+	// Tags are the tags of the page.
+	Tags []string `json:"tags"`
+	// Series is the series the page is a part of.
+	Series *Series `json:"series,omitempty"`
+	// Previous is the page before this one, in its series or directory.
+	Previous *Neighbour `json:"previous,omitempty"`
+	// Next is the page after this one, in its series or directory.
+	Next *Neighbour `json:"next,omitempty"`
+	// Backlinks are the pages linking to this one.
+	Backlinks []Neighbour `json:"backlinks,omitempty"`
+	// Links are the extra links of the page, like its pagination.
+	Links []Link `json:"links,omitempty"`
+	// Accoutrement are the page's settings.
+	Accoutrement Accoutrement `json:"accoutrement"`
+	// Scripts are the extra scripts of the page.
+	Scripts []string `json:"scripts"`
+	// Stylesheets are the extra stylesheets of the page.
+	Stylesheets []string `json:"stylesheets"`
+	// HtmlHead are the extra html head declarations of the page.
+	HtmlHead []string `json:"html_head"`
+	// Footnotes are the footnotes of the page, in order.
+	Footnotes []string `json:"footnotes"`
+	// Contents are the contents of the page, in order.
+	Contents []Content `json:"contents"`
+}
+
+// Accoutrement is the exported `yunyun.Accoutrement`, where flips are
+// one of "default", "enabled", or "disabled".
+type Accoutrement struct {
+	Preview                 string   `json:"preview,omitempty"`
+	PreviewWidth            string   `json:"preview_width,omitempty"`
+	PreviewHeight           string   `json:"preview_height,omitempty"`
+	PreviewGenerate         string   `json:"preview_generate"`
+	ExcludeHtmlHeadContains []string `json:"exclude_html_head_contains"`
+	Draft                   string   `json:"draft"`
+	Tomb                    string   `json:"tomb"`
+	AuthorImage             string   `json:"author_image"`
+	Math                    string   `json:"math"`
+	Toc                     string   `json:"toc"`
+	Sitemap                 string   `json:"sitemap"`
+	SitemapPriority         string   `json:"sitemap_priority,omitempty"`
+	SitemapChangefreq       string   `json:"sitemap_changefreq,omitempty"`
+	AutoIndex               string   `json:"auto_index"`
+}
+
+// Content is the exported `yunyun.Content`. Text fields keep darkness'
+// inline markup, so consumers can decide how to render it. Only the
+// fields that are set are exported.
+type Content struct {
+	// Type is one of the `contentTypes` names.
+	Type string `json:"type"`
+	// Flags are the `contentFlags` names set on the content.
+	Flags []string `json:"flags,omitempty"`
+
+	Heading              string `json:"heading,omitempty"`
+	HeadingLevel         uint32 `json:"heading_level,omitempty"`
+	HeadingLevelAdjusted uint32 `json:"heading_level_adjusted,omitempty"`
+	HeadingFirst         bool   `json:"heading_first,omitempty"`
+	HeadingLast          bool   `json:"heading_last,omitempty"`
+	HeadingChild         bool   `json:"heading_child,omitempty"`
+
+	Paragraph string `json:"paragraph,omitempty"`
+
+	List []ListItem `json:"list,omitempty"`
+
+	Link            string `json:"link,omitempty"`
+	LinkTitle       string `json:"link_title,omitempty"`
+	LinkDescription string `json:"link_description,omitempty"`
+
+	SourceCode     string `json:"source_code,omitempty"`
+	SourceCodeLang string `json:"source_code_lang,omitempty"`
+
+	RawHtml string `json:"raw_html,omitempty"`
+
+	AttentionTitle string `json:"attention_title,omitempty"`
+	AttentionText  string `json:"attention_text,omitempty"`
+
+	Table        [][]string `json:"table,omitempty"`
+	TableHeaders bool       `json:"table_headers,omitempty"`
+
+	Summary string `json:"summary,omitempty"`
+
+	GalleryPath         string `json:"gallery_path,omitempty"`
+	GalleryImagesPerRow uint   `json:"gallery_images_per_row,omitempty"`
+
+	Caption        string `json:"caption,omitempty"`
+	Attributes     string `json:"attributes,omitempty"`
+	CustomHtmlTags string `json:"custom_html_tags,omitempty"`
+}
+
+// Enclosure is the exported `yunyun.Enclosure`.
+type Enclosure struct {
+	Link     string `json:"link"`
+	Duration string `json:"duration,omitempty"`
+	Episode  string `json:"episode,omitempty"`
+	Explicit bool   `json:"explicit"`
+}
+
+// Series is the exported `yunyun.Series`.
+type Series struct {
+	Name  string `json:"name"`
+	Order int    `json:"order,omitempty"`
+}
+
+// Neighbour is the exported `yunyun.Neighbour`.
+type Neighbour struct {
+	Title    string `json:"title"`
+	Location string `json:"location"`
+	Url      string `json:"url"`
+}
+
+// Link is the exported `yunyun.Link`.
+type Link struct {
+	Rel  string `json:"rel"`
+	Type string `json:"type,omitempty"`
+	Href string `json:"href"`
+}
+
+// ListItem is the exported `yunyun.ListItem`.
+type ListItem struct {
+	Level uint8  `json:"level"`
+	Text  string `json:"text"`
+}
+
+// contentTypes are the schema names of content types, which must
+// never change, as opposed to the order of `yunyun.TypeContent`.
+var contentTypes = map[yunyun.TypeContent]string{
+	yunyun.TypeHeading:        "heading",
+	yunyun.TypeParagraph:      "paragraph",
+	yunyun.TypeList:           "list",
+	yunyun.TypeListNumbered:   "list_numbered",
+	yunyun.TypeLink:           "link",
+	yunyun.TypeSourceCode:     "source_code",
+	yunyun.TypeRawHtml:        "raw_html",
+	yunyun.TypeHorizontalLine: "horizontal_line",
+	yunyun.TypeAttentionText:  "attention",
+	yunyun.TypeTable:          "table",
+	yunyun.TypeDetails:        "details",
+}
+
+// contentFlags are the schema names of content flags, in the order
+// they're exported. Internal parser states are left out.
+var contentFlags = []struct {
+	flag yunyun.Bits
+	name string
+}{
+	{yunyun.InQuoteFlag, "quote"},
+	{yunyun.InCenterFlag, "center"},
+	{yunyun.InDetailsFlag, "details"},
+	{yunyun.InDropCapFlag, "drop_cap"},
+	{yunyun.InGalleryFlag, "gallery"},
+	{yunyun.InRawHtmlFlagUnsafe, "raw_html_unsafe"},
+	{yunyun.InRawHtmlFlagResponsive, "raw_html_responsive"},
+}
+
+// accoutrementFlips are the schema names of accoutrement flips.
+var accoutrementFlips = map[yunyun.AccoutrementFlip]string{
+	yunyun.AccoutrementDefault:  "default",
+	yunyun.AccoutrementEnabled:  "enabled",
+	yunyun.AccoutrementDisabled: "disabled",
+}
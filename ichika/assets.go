@@ -0,0 +1,74 @@
+package ichika
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/emilia/puck"
+	"github.com/thecsw/darkness/ichika/hizuru"
+	"github.com/thecsw/darkness/yunyun"
+	"github.com/thecsw/komi"
+	"github.com/thecsw/rei"
+)
+
+// copyAssets copies all the static files over to the output directory, files
+// that haven't changed since the last copy are skipped.
+func copyAssets(conf *alpha.DarknessConfig) {
+	if !conf.Project.HasOutputDirectory() {
+		return
+	}
+	defer puck.Stopwatch("Copied assets").Record(puck.Logger)
+
+	copyPool := komi.NewWithSettings(komi.WorkSimpleWithErrors(func(source yunyun.FullPathFile) error {
+		return copyAsset(source, conf.Project.SourceToOutput(source))
+	}), &komi.Settings{
+		Name:     "Komi Copying 📦 ",
+		Laborers: runtime.NumCPU(),
+		Debug:    debugEnabled,
+	})
+	go logErrors("copying", rei.Must(copyPool.Errors()))
+
+	assets := make(chan yunyun.FullPathFile, 8)
+	go hizuru.FindAssets(conf, assets)
+	for asset := range assets {
+		rei.Try(copyPool.Submit(asset))
+	}
+	copyPool.Close()
+}
+
+// copyAsset copies the source file to target, unless target is already
+// of the same size and not older than the source.
+func copyAsset(source, target yunyun.FullPathFile) error {
+	sourceInfo, err := os.Stat(string(source))
+	if err != nil {
+		return fmt.Errorf("reading asset %s: %v", source, err)
+	}
+	if targetInfo, err := os.Stat(string(target)); err == nil &&
+		targetInfo.Size() == sourceInfo.Size() && !targetInfo.ModTime().Before(sourceInfo.ModTime()) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(string(target)), 0o755); err != nil {
+		return fmt.Errorf("creating directory for %s: %v", target, err)
+	}
+	in, err := os.Open(string(source))
+	if err != nil {
+		return fmt.Errorf("opening asset %s: %v", source, err)
+	}
+	defer in.Close()
+	out, err := os.Create(string(target))
+	if err != nil {
+		return fmt.Errorf("creating asset %s: %v", target, err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("copying asset %s: %v", source, err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("closing asset %s: %v", target, err)
+	}
+	return nil
+}
@@ -1,8 +1,11 @@
 package ichika
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"runtime"
+	"sync"
 	"time"
 
 	"github.com/thecsw/darkness/emilia/alpha"
@@ -10,9 +13,12 @@ import (
 	"github.com/thecsw/darkness/export"
 	"github.com/thecsw/darkness/ichika/akane"
 	"github.com/thecsw/darkness/ichika/hizuru"
+	"github.com/thecsw/darkness/ichika/kazuma"
 	"github.com/thecsw/darkness/ichika/makima"
+	"github.com/thecsw/darkness/ichika/misa"
 	"github.com/thecsw/darkness/parse"
 	"github.com/thecsw/darkness/yunyun"
+	"github.com/thecsw/gana"
 	"github.com/thecsw/komi"
 	"github.com/thecsw/rei"
 )
@@ -23,14 +29,69 @@ var akaneless = false
 func BuildCommandFunc() {
 	cmd := darknessFlagset(buildCommand)
 	conf := alpha.BuildConfig(getAlphaOptions(cmd))
-	build(conf)
+	if err := build(conf); err != nil {
+		puck.Logger.Fatalf("building: %v", err)
+	}
 	fmt.Println("farewell")
 }
 
-// build uses set flags and emilia data to build the local directory.
-func build(conf *alpha.DarknessConfig) {
-	parser := parse.BuildParser(conf)
-	exporter := export.BuildExporter(conf)
+// build uses set flags and emilia data to build the local directory, and
+// returns the errors of writing the feeds, sitemaps, and other generated files.
+func build(conf *alpha.DarknessConfig) error {
+	// Static assets go to the output directory last, so akane's
+	// generated previews are copied too.
+	defer copyAssets(conf)
+
+	// Find all the files that need to be parsed.
+	inputFilenames := make(chan yunyun.FullPathFile, 8)
+	go hizuru.FindFilesByExt(conf, inputFilenames)
+	return buildFiles(conf, kazuma.Open(conf, forceRebuild), inputFilenames, false)
+}
+
+// rebuild only builds the passed input files and the pages that depend on
+// them, the rest is left as is. Feeds, sitemaps, and other generated files
+// are made out of every page, so when any of them is enabled, or the cache
+// is ignored, the whole project is walked again, the cache still skips
+// exporting the pages that didn't change.
+func rebuild(conf *alpha.DarknessConfig, files []yunyun.FullPathFile) error {
+	cache := kazuma.Open(conf, forceRebuild)
+	if keepsPages(conf) || forceRebuild {
+		inputFilenames := make(chan yunyun.FullPathFile, 8)
+		go hizuru.FindFilesByExt(conf, inputFilenames)
+		return buildFiles(conf, cache, inputFilenames, false)
+	}
+	// Auto indices and pages in series depend on the others, so they're rebuilt with them.
+	for _, dynamic := range cache.Dynamic() {
+		file := conf.Runtime.WorkDir.Join(dynamic)
+		if _, err := os.Stat(string(file)); err == nil && !gana.Any(file, files) {
+			files = append(files, file)
+		}
+	}
+	inputFilenames := make(chan yunyun.FullPathFile, len(files))
+	for _, file := range files {
+		inputFilenames <- file
+	}
+	close(inputFilenames)
+	return buildFiles(conf, cache, inputFilenames, true)
+}
+
+// buildFiles pushes the input files through the pools, partial builds
+// keep the cache entries of the pages that weren't passed.
+func buildFiles(conf *alpha.DarknessConfig, cache *kazuma.Cache, inputFilenames <-chan yunyun.FullPathFile, partial bool) error {
+	parsers := parse.BuildParsers(conf)
+	exporters, err := export.BuildExporters(conf)
+	if err != nil {
+		return err
+	}
+
+	// Feeds, sitemaps, and other generated files are made from the parsed pages once the build is done,
+	// so cached pages are parsed too.
+	keepPages := keepsPages(conf)
+	// Pages that were parsed, dependent pages are built out of them too.
+	pages := make([]*yunyun.Page, 0, 64)
+	// Auto indices and pages with neighbours wait for every page to be parsed.
+	dependents := make([]makima.Woof, 0, 4)
+	pagesLock := &sync.Mutex{}
 
 	if !akaneless {
 		// Let's complete the akane requests when done building.
@@ -46,76 +107,182 @@ func build(conf *alpha.DarknessConfig) {
 	filesError := rei.Must(filesPool.Errors())
 	go logErrors("reading", filesError)
 
-	// Create a pool that take a files handle and parses it out into yunyun pages.
-	parserPool := komi.NewWithSettings(komi.Work(makima.Woof.Parse), &komi.Settings{
-		Name:     "Komi Parsing 🧹 ",
-		Laborers: customNumWorkers,
-		Debug:    debugEnabled,
-	})
+	// Each output gets its own pair of pools that export the shared
+	// yunyun pages and write them to target files.
+	exporterPools := make(map[string]*komi.Pool[makima.Woof, makima.Woof], len(exporters))
+	closeWriterPools := make([]func(...bool), 0, len(exporters))
+	for output := range exporters {
+		// Create a pool that that takes yunyun pages and exports them into request format.
+		exporterPools[output] = komi.NewWithSettings(komi.Work(makima.Woof.Export), &komi.Settings{
+			Name:     "Komi Exporting 🥂 " + output + " ",
+			Laborers: customNumWorkers,
+			Debug:    debugEnabled,
+		})
 
-	// Create a pool that that takes yunyun pages and exports them into request format.
-	exporterPool := komi.NewWithSettings(komi.Work(makima.Woof.Export), &komi.Settings{
-		Name:     "Komi Exporting 🥂 ",
-		Laborers: customNumWorkers,
-		Debug:    debugEnabled,
-	})
+		// Create a pool that reads the exported data and writes them to target files.
+		writerPool := komi.NewWithSettings(komi.WorkSimpleWithErrors(makima.Woof.Write), &komi.Settings{
+			Name:     "Komi Writing 🎸 " + output + " ",
+			Laborers: runtime.NumCPU(),
+			Debug:    debugEnabled,
+		})
+		go logErrors("writer", rei.Must(writerPool.Errors()))
 
-	// Create a pool that reads the exported data and writes them to target files.
-	writerPool := komi.NewWithSettings(komi.WorkSimpleWithErrors(makima.Woof.Write), &komi.Settings{
-		Name:     "Komi Writing 🎸",
-		Laborers: runtime.NumCPU(),
+		rei.Try(exporterPools[output].Connect(writerPool))
+		closeWriterPools = append(closeWriterPools, writerPool.Close)
+	}
+
+	// Create a pool that take a files handle and parses it out into yunyun pages,
+	// then hands the same page over to every output's exporter pool.
+	parserPool := komi.NewWithSettings(komi.WorkSimple(func(c makima.Woof) {
+		parsed := c.Parse()
+		if parsed.Parsed() != nil {
+			pagesLock.Lock()
+			pages = append(pages, parsed.Parsed())
+			pagesLock.Unlock()
+		}
+		if page := parsed.Parsed(); page != nil && misa.Dependent(conf, page) {
+			pagesLock.Lock()
+			dependents = append(dependents, parsed)
+			pagesLock.Unlock()
+			return
+		}
+		for output, exporter := range exporters {
+			rei.Try(exporterPools[output].Submit(parsed.For(output, exporter)))
+		}
+	}), &komi.Settings{
+		Name:     "Komi Parsing 🧹 ",
+		Laborers: customNumWorkers,
 		Debug:    debugEnabled,
 	})
-	writersErrors := rei.Must(writerPool.Errors())
-	go logErrors("writer", writersErrors)
 
 	// Connect all the pools between each other, so the relationship is as follows,
 	//
 	//           Reading 📚                      Parsing 🧹
-	//   path  ┌───────────┐   file handler   ┌─────────────┐
-	// ──────> │ filesPool │ ───────────────> │  parserPool │
-	//         └───────────┘                  └─────────────┘
-	//          log errors                          │
-	//                                              │    parsed files
-	//                                              │  aka yunyun pages
-	//                                              │
+	//   path  ┌───────────┐   file handler   ┌────────────┐
+	// ──────> │ filesPool │ ───────────────> │ parserPool │
+	//         └───────────┘                  └────────────┘
+	//          log errors                          │   parsed files
+	//                                              │ aka yunyun pages
+	//                                              │ (one per output)
+	//                                              v
 	//   file  ┌────────────┐  exported data  ┌──────────────┐
 	//  <───── │ writerPool │ <────────────── │ exporterPool │
-	//         └────────────┘              	  └──────────────┘
+	//         └────────────┘                 └──────────────┘
 	//           Writing 🎸                     Exporting 🥂
 	//
+	// Pages that depend on others skip the exporters until every page is parsed.
 	rei.Try(filesPool.Connect(parserPool))
-	rei.Try(parserPool.Connect(exporterPool))
-	rei.Try(exporterPool.Connect(writerPool))
 
 	// Record the start time.
 	start := time.Now()
 
-	// Find all the files that need to be parsed.
-	inputFilenames := make(chan yunyun.FullPathFile, 8)
-	go hizuru.FindFilesByExt(conf, inputFilenames)
-
 	// Submit all the files to the pool.
 	for inputFilename := range inputFilenames {
 		// Submit the job to the pool.
 		rei.Try(filesPool.Submit(&makima.Control{
 			Conf:          conf,
-			Parser:        parser,
-			Exporter:      exporter,
+			Parser:        parsers.For(inputFilename),
 			InputFilename: inputFilename,
+			Cache:         cache,
+			KeepPage:      keepPages,
 		}))
 	}
 
-	// Wait for all the pools to finish.
-	writerPool.Close()
+	// Wait for all the pages to be parsed, then for all the outputs to finish.
+	parserPool.Close()
+
+	// Generated pages don't come from any file, so they skip reading and
+	// parsing, and go straight to the exporters.
+	generated := make([]*yunyun.Page, 0, 16)
+
+	// Backlinks are collected with the neighbours, the graph is written with them too.
+	var links *misa.Links
+
+	// Now that every page is parsed, auto indices can list them and pages
+	// can find their neighbours and backlinks. Partial builds and cached pages leave some of
+	// the pages unparsed, so only those are built here.
+	if len(dependents) > 0 {
+		allPages := pages
+		if !keepPages || partial {
+			allPages = misa.CompletePages(conf, pages)
+		}
+		neighbours := misa.BuildNeighbours(conf, allPages)
+		if conf.Backlinks.Enable {
+			links = misa.BuildLinks(conf, allPages)
+		}
+		for _, dependent := range dependents {
+			page := dependent.Parsed()
+			neighbours.Apply(page)
+			if links != nil {
+				links.Apply(page)
+			}
+			dependent.Context(misa.Context(page))
+			// Listings are built on copies, the parsed pages go to the feeds and search as they are.
+			if page.Accoutrement.AutoIndex.IsEnabled() {
+				if listings := misa.AutoIndex(conf, page, allPages); len(listings) > 0 {
+					dependent.Use(listings[0])
+					generated = append(generated, listings[1:]...)
+				}
+			}
+			for output, exporter := range exporters {
+				rei.Try(exporterPools[output].Submit(dependent.For(output, exporter)))
+			}
+		}
+	}
+	if conf.Tags.Enable {
+		generated = append(generated, misa.TagPages(conf, pages)...)
+	}
+	if conf.Archive.Enable {
+		generated = append(generated, misa.ArchivePages(conf, pages)...)
+	}
+	for _, page := range generated {
+		control := &makima.Control{Conf: conf, InputFilename: conf.Runtime.WorkDir.Join(page.File), Page: page}
+		for output, exporter := range exporters {
+			rei.Try(exporterPools[output].Submit(control.For(output, exporter)))
+		}
+	}
+
+	for _, closeWriterPool := range closeWriterPools {
+		closeWriterPool()
+	}
+
+	// Write the feeds, sitemaps, search indices, and link graphs out of the same pages that were exported.
+	errs := make([]error, 0, 4)
+	if len(conf.RSS.Feeds) > 0 {
+		errs = append(errs, misa.WriteFeeds(conf, pages, false))
+	}
+	if conf.Sitemap.Enable {
+		errs = append(errs, misa.WriteSitemap(conf, append(pages, generated...), false))
+	}
+	if conf.Sitemap.Robots {
+		errs = append(errs, misa.WriteRobots(conf, false))
+	}
+	if conf.Search.Enable {
+		errs = append(errs, misa.WriteSearch(conf, pages, false))
+	}
+	if links != nil {
+		errs = append(errs, misa.WriteGraph(conf, links, false))
+	}
 
 	// Record the time it took to finish.
 	finish := time.Now()
 
+	// Remember what we built for the next time.
+	if err := cache.Save(partial); err != nil {
+		puck.Logger.Error("Saving build cache", "err", err)
+	}
+
 	// Clear the download progress bar if present by wiping out the line.
 	fmt.Print("\r\033[2K")
 
-	fmt.Printf("Processed %d files in %d ms\n", exporterPool.JobsSucceeded(), finish.Sub(start).Milliseconds())
+	fmt.Printf("Processed %d files (%d cached) in %d ms\n",
+		parserPool.JobsSucceeded(), cache.Hits(), finish.Sub(start).Milliseconds())
+	return errors.Join(errs...)
+}
+
+// keepsPages returns true if the pages are needed after the build.
+func keepsPages(conf *alpha.DarknessConfig) bool {
+	return len(conf.RSS.Feeds) > 0 || conf.Sitemap.Enable || conf.Search.Enable || conf.Tags.Enable || conf.Archive.Enable || conf.Siblings.Enable || conf.Backlinks.Enable
 }
 
 // logErrors is a helper function that logs errors from a pool. It is meant to be
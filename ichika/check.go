@@ -0,0 +1,39 @@
+package ichika
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/emilia/puck"
+	"github.com/thecsw/darkness/ichika/misa"
+)
+
+// CheckCommandFunc reports every link, image, and media file that points
+// to nothing, and exits with an error if there are any.
+func CheckCommandFunc() {
+	checkCmd := darknessFlagset(checkCommand)
+	external := checkCmd.Bool("external", false, "also check links to other websites")
+	endpoint := checkCmd.String("endpoint", "", "check external links by asking this url")
+	options := getAlphaOptions(checkCmd)
+
+	puck.Logger.SetPrefix("Check 🔍 ")
+
+	conf := alpha.BuildConfig(options)
+	if *external {
+		conf.Check.External = true
+	}
+	if len(*endpoint) > 0 {
+		conf.Check.Endpoint = *endpoint
+	}
+
+	problems := misa.Check(conf, misa.AllPages(conf))
+	for _, problem := range problems {
+		fmt.Println(problem)
+	}
+	if len(problems) > 0 {
+		fmt.Printf("Found %d broken links\n", len(problems))
+		os.Exit(1)
+	}
+	fmt.Println("No broken links found")
+}
@@ -45,6 +45,9 @@ var (
 	// will be skipped in discovery process AND should be put it
 	// .gitignore by user, so they don't pollute their git objects.
 	vendorGalleryImages bool
+
+	// forceRebuild ignores the build cache and rebuilds every page.
+	forceRebuild bool
 )
 
 // getAlphaOptions takes a cmd subcommand and parses general flags
@@ -59,6 +62,7 @@ func getAlphaOptions(cmd *flag.FlagSet) alpha.Options {
 	cmd.BoolVar(&useCurrentDirectory, "dev", false, "use local path for urls (development)")
 	cmd.BoolVar(&vendorGalleryImages, "vendor-galleries", false, "stub in local copies of gallery links (SLOW)")
 	cmd.BoolVar(&akaneless, "akaneless", false, "skip akane processing")
+	cmd.BoolVar(&forceRebuild, "force", false, "ignore the build cache and rebuild everything")
 	if err := cmd.Parse(os.Args[2:]); err != nil {
 		puck.Logger.Fatalf("parsing build arguments: %v", err)
 	}
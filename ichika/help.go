@@ -21,6 +21,7 @@ Here are the commands you can use, -help is supported:
   megumin - blow up the directory!!
   clean - megumin but super boring
   misa - supercharge your website
+  check - find broken links and files
   lalatina - pls dont
   aqua - ...
 
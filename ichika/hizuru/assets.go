@@ -0,0 +1,72 @@
+package hizuru
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/karrick/godirwalk"
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/yunyun"
+	g "github.com/thecsw/gana"
+	"github.com/thecsw/rei"
+)
+
+// FindAssets finds all the static files (styles, scripts, images, etc.) that
+// should be copied over to the output directory.
+func FindAssets(conf *alpha.DarknessConfig, assets chan<- yunyun.FullPathFile) {
+	if err := godirwalk.Walk(string(conf.Runtime.WorkDir), &godirwalk.Options{
+		ErrorCallback: func(osPathname string, err error) godirwalk.ErrorAction {
+			conf.Runtime.Logger.Errorf("traversing %s: %v", osPathname, err)
+			return godirwalk.SkipNode
+		},
+		Unsorted: true,
+		Callback: func(osPathname string, de *godirwalk.Dirent) error {
+			if de.IsDir() {
+				if isSkippedDirectory(conf, osPathname, de) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !isAsset(conf, osPathname) {
+				return nil
+			}
+			assets <- yunyun.FullPathFile(osPathname)
+			return nil
+		},
+	}); err != nil {
+		conf.Runtime.Logger.Errorf("root traversal: %v", err)
+	}
+	close(assets)
+}
+
+// FindAssetsSimple is the same as `FindAssets` but it simply blocks the
+// parent goroutine until it processes all the results.
+func FindAssetsSimple(conf *alpha.DarknessConfig) []yunyun.FullPathFile {
+	c := make(chan yunyun.FullPathFile)
+	go FindAssets(conf, c)
+	return rei.Collect(c)
+}
+
+// isAsset returns true if the file is not an input, not hidden, not
+// excluded or ignored, and not a stale output of one of the inputs or the build.
+func isAsset(conf *alpha.DarknessConfig, osPathname string) bool {
+	if conf.Project.IsInput(osPathname) ||
+		conf.IsGenerated(conf.Runtime.WorkDir.Rel(yunyun.FullPathFile(osPathname))) ||
+		strings.HasPrefix(filepath.Base(osPathname), ".") ||
+		osPathname == conf.Runtime.ConfigFile ||
+		conf.Project.IsTemplate(yunyun.FullPathFile(osPathname)) ||
+		conf.Project.IsIgnored(yunyun.FullPathFile(osPathname)) ||
+		(conf.Project.ExcludeEnabled && conf.Project.ExcludeRegex.MatchString(osPathname)) {
+		return false
+	}
+	if !conf.Project.IsOutput(osPathname) {
+		return true
+	}
+	// Outputs built next to their sources are not assets.
+	stem := strings.TrimSuffix(osPathname, filepath.Ext(osPathname))
+	return !g.Anyf(func(input string) bool {
+		_, err := os.Stat(stem + input)
+		return err == nil
+	}, conf.Project.Inputs)
+}
@@ -0,0 +1,53 @@
+package hizuru
+
+import (
+	"testing"
+
+	"github.com/thecsw/darkness/internal/testutil"
+	"github.com/thecsw/darkness/yunyun"
+)
+
+func TestFindAssetsSkipsGeneratedFiles(t *testing.T) {
+	conf := testutil.Project(t, map[string]string{
+		"darkness.toml": "url = \"https://example.com\"\n\n[project]\noutput_dir = \"public\"\n\n[[rss.feeds]]\nfilename = \"feed.xml\"\n",
+		"index.org":     "#+title: Home\n",
+		"feed.xml":      "<rss>stale</rss>\n",
+		"style.css":     "body {}\n",
+	})
+
+	found := make(map[yunyun.RelativePathFile]bool)
+	for _, asset := range FindAssetsSimple(conf) {
+		found[conf.Runtime.WorkDir.Rel(asset)] = true
+	}
+	if found["feed.xml"] {
+		t.Error("stale feed.xml is copied over the generated one")
+	}
+	if !found["style.css"] {
+		t.Error("style.css is not copied")
+	}
+}
+
+func TestFindAssetsSkipsProjectFiles(t *testing.T) {
+	conf := testutil.Project(t, map[string]string{
+		"darkness.toml":   "url = \"https://example.com\"\n\n[project]\noutput_dir = \"public\"\nignore = [\"drafts/*.txt\"]\n",
+		"README.md":       "# Site\n",
+		"LICENSE":         "MIT\n",
+		"go.mod":          "module site\n",
+		"deploy.sh":       "#!/bin/sh\n",
+		"drafts/idea.txt": "later\n",
+		"notes.txt":       "hello\n",
+	})
+
+	found := make(map[yunyun.RelativePathFile]bool)
+	for _, asset := range FindAssetsSimple(conf) {
+		found[conf.Runtime.WorkDir.Rel(asset)] = true
+	}
+	for _, ignored := range []yunyun.RelativePathFile{"README.md", "LICENSE", "go.mod", "deploy.sh", "drafts/idea.txt"} {
+		if found[ignored] {
+			t.Errorf("%s is not a site asset", ignored)
+		}
+	}
+	if !found["notes.txt"] {
+		t.Error("notes.txt is not copied")
+	}
+}
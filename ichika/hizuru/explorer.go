@@ -15,7 +15,7 @@ import (
 	"github.com/thecsw/rei"
 )
 
-// FindFilesByExt finds all files with a given extension.
+// FindFilesByExt finds all files with the project's input extensions.
 func FindFilesByExt(conf *alpha.DarknessConfig, inputFiles chan<- yunyun.FullPathFile) {
 	if err := godirwalk.Walk(string(conf.Runtime.WorkDir), &godirwalk.Options{
 		ErrorCallback: func(osPathname string, err error) godirwalk.ErrorAction {
@@ -24,7 +24,10 @@ func FindFilesByExt(conf *alpha.DarknessConfig, inputFiles chan<- yunyun.FullPat
 		},
 		Unsorted: true,
 		Callback: func(osPathname string, de *godirwalk.Dirent) error {
-			if filepath.Ext(osPathname) != conf.Project.Input || strings.HasPrefix(filepath.Base(osPathname), ".") {
+			if de.IsDir() && isSkippedDirectory(conf, osPathname, de) {
+				return filepath.SkipDir
+			}
+			if !conf.Project.IsInput(osPathname) || strings.HasPrefix(filepath.Base(osPathname), ".") {
 				return nil
 			}
 			if (conf.Project.ExcludeEnabled && conf.Project.ExcludeRegex.MatchString(osPathname)) ||
@@ -44,6 +47,16 @@ func FindFilesByExt(conf *alpha.DarknessConfig, inputFiles chan<- yunyun.FullPat
 	close(inputFiles)
 }
 
+// isSkippedDirectory returns true if the directory should never be walked,
+// which are hidden directories and the output directory.
+func isSkippedDirectory(conf *alpha.DarknessConfig, osPathname string, de *godirwalk.Dirent) bool {
+	if osPathname == string(conf.Runtime.WorkDir) {
+		return false
+	}
+	return strings.HasPrefix(de.Name(), ".") ||
+		(conf.Project.HasOutputDirectory() && osPathname == string(conf.Project.OutputRoot()))
+}
+
 // FindFilesByExtSimple is the same as `FindFilesByExt` but it simply blocks the
 // parent goroutine until it processes all the results.
 func FindFilesByExtSimple(conf *alpha.DarknessConfig) []yunyun.FullPathFile {
@@ -72,9 +85,24 @@ func findFilesByExtSimpleDirs(conf *alpha.DarknessConfig, dirs []string) []yunyu
 
 // BuildPagesSimple will return a slice of built pages that have dirs as parents (empty dirs will return everything).
 func BuildPagesSimple(conf *alpha.DarknessConfig, dirs []string) []*yunyun.Page {
-	inputFilenames := findFilesByExtSimpleDirs(conf, dirs)
+	return buildPages(conf, findFilesByExtSimpleDirs(conf, dirs))
+}
+
+// BuildPagesMissing will return a slice of built pages of the project that are not among the passed pages.
+func BuildPagesMissing(conf *alpha.DarknessConfig, pages []*yunyun.Page) []*yunyun.Page {
+	built := make(map[yunyun.RelativePathFile]bool, len(pages))
+	for _, page := range pages {
+		built[page.File] = true
+	}
+	return buildPages(conf, g.Filter(func(inputFilename yunyun.FullPathFile) bool {
+		return !built[conf.Runtime.WorkDir.Rel(inputFilename)]
+	}, FindFilesByExtSimple(conf)))
+}
+
+// buildPages parses the input files into pages, skipping the ones that can't be read.
+func buildPages(conf *alpha.DarknessConfig, inputFilenames []yunyun.FullPathFile) []*yunyun.Page {
 	pages := make([]*yunyun.Page, 0, len(inputFilenames))
-	parser := parse.BuildParser(conf)
+	parsers := parse.BuildParsers(conf)
 	for _, inputFilename := range inputFilenames {
 		bundleOption := openFile(inputFilename)
 		if bundleOption.IsNone() {
@@ -86,12 +114,39 @@ func BuildPagesSimple(conf *alpha.DarknessConfig, dirs []string) []*yunyun.Page
 			puck.Logger.Printf("reading file %s: %v", inputFilename, err)
 			continue
 		}
-		page := parser.Do(conf.Runtime.WorkDir.Rel(bundle.First), string(data))
+		page := parsers.For(inputFilename).Do(conf.Runtime.WorkDir.Rel(bundle.First), string(data))
 		if page == nil {
-			logger.Warnf("Parser produced a nil page", "input", conf.Runtime.WorkDir.Rel(bundle.First))
+			logger.Warn("Parser produced a nil page", "input", conf.Runtime.WorkDir.Rel(bundle.First))
 			continue
 		}
 		pages = append(pages, page)
 	}
 	return pages
 }
+
+// FindDirectories finds all the directories under root that are a part of
+// the project, which excludes hidden, excluded, and output directories.
+func FindDirectories(conf *alpha.DarknessConfig, root yunyun.FullPathDir) []yunyun.FullPathDir {
+	dirs := make([]yunyun.FullPathDir, 0, 16)
+	if err := godirwalk.Walk(string(root), &godirwalk.Options{
+		ErrorCallback: func(osPathname string, err error) godirwalk.ErrorAction {
+			conf.Runtime.Logger.Errorf("traversing %s: %v", osPathname, err)
+			return godirwalk.SkipNode
+		},
+		Unsorted: true,
+		Callback: func(osPathname string, de *godirwalk.Dirent) error {
+			if !de.IsDir() {
+				return nil
+			}
+			if isSkippedDirectory(conf, osPathname, de) ||
+				(conf.Project.ExcludeEnabled && conf.Project.ExcludeRegex.MatchString(osPathname+"/")) {
+				return filepath.SkipDir
+			}
+			dirs = append(dirs, yunyun.FullPathDir(osPathname))
+			return nil
+		},
+	}); err != nil {
+		conf.Runtime.Logger.Errorf("root traversal: %v", err)
+	}
+	return dirs
+}
@@ -0,0 +1,20 @@
+package hizuru
+
+import (
+	"testing"
+
+	"github.com/thecsw/darkness/internal/testutil"
+	"github.com/thecsw/darkness/yunyun"
+)
+
+func TestBuildPagesMissing(t *testing.T) {
+	conf := testutil.Project(t, map[string]string{
+		"index.org":      "#+title: Home\n",
+		"blog/index.org": "#+title: Blog\n",
+	})
+	parsed := yunyun.NewPage(yunyun.WithFilename("index.org"), yunyun.WithLocation("."))
+	missing := BuildPagesMissing(conf, []*yunyun.Page{parsed})
+	if len(missing) != 1 || missing[0].File != "blog/index.org" {
+		t.Errorf("missing = %+v, want only blog/index.org", missing)
+	}
+}
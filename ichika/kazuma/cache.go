@@ -0,0 +1,239 @@
+package kazuma
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/yunyun"
+	"github.com/thecsw/rei"
+)
+
+const (
+	// cacheDirectory is where darkness keeps its own state.
+	cacheDirectory = ".darkness"
+	// cacheFilename is the filename of the cache in the cache directory.
+	cacheFilename = "cache"
+	// cacheVersion should be bumped whenever the cache format changes.
+	cacheVersion = 3
+)
+
+// Entry is what we remember about a single page.
+type Entry struct {
+	// Input is the hash of the page's source.
+	Input string `json:"input"`
+	// Config is the hash of the config the page was built with.
+	Config string `json:"config"`
+	// Outputs are the hashes of the files we wrote keyed by their extensions.
+	Outputs map[string]string `json:"outputs"`
+	// Dynamic pages depend on other pages, so they're never fresh.
+	Dynamic bool `json:"dynamic,omitempty"`
+	// Context is the hash of what the page takes from other pages, like
+	// its neighbours, the page is only fresh while it stays the same.
+	Context string `json:"context,omitempty"`
+}
+
+// Cache is the persistent build cache, safe for concurrent use.
+type Cache struct {
+	// Version is the format version of the cache.
+	Version int `json:"version"`
+	// Entries are the cached pages keyed by their relative path.
+	Entries map[yunyun.RelativePathFile]Entry `json:"entries"`
+
+	// path is the location of the cache file.
+	path string
+	// conf is the config the pages are built with.
+	conf *alpha.DarknessConfig
+	// config is the current config hash.
+	config string
+	// seen are the pages that were a part of this build.
+	seen map[yunyun.RelativePathFile]struct{}
+	// hits is the number of pages that were skipped.
+	hits int
+	// mu guards everything above.
+	mu sync.Mutex
+}
+
+// Open reads the cache of the project, a broken or missing cache is
+// simply an empty one. If force is true, previous entries are ignored.
+func Open(conf *alpha.DarknessConfig, force bool) *Cache {
+	c := &Cache{
+		Version: cacheVersion,
+		Entries: make(map[yunyun.RelativePathFile]Entry),
+		path:    string(conf.Runtime.WorkDir.Join(yunyun.RelativePathFile(filepath.Join(cacheDirectory, cacheFilename)))),
+		conf:    conf,
+		config:  fingerprint(conf),
+		seen:    make(map[yunyun.RelativePathFile]struct{}),
+	}
+	if force {
+		return c
+	}
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logger.Warn("Reading cache, starting fresh", "path", c.path, "err", err)
+		}
+		return c
+	}
+	previous := &Cache{}
+	if err := json.Unmarshal(data, previous); err != nil || previous.Version != cacheVersion {
+		logger.Warn("Cache is invalid, starting fresh", "path", c.path)
+		return c
+	}
+	if previous.Entries != nil {
+		c.Entries = previous.Entries
+	}
+	return c
+}
+
+// Fresh returns true if the page with the given input hash was already
+// built with the current config and all of its outputs, which are the
+// output filenames keyed by their extensions, are still intact.
+func (c *Cache) Fresh(file yunyun.RelativePathFile, input string, outputs map[string]string) bool {
+	c.mu.Lock()
+	c.seen[file] = struct{}{}
+	entry, ok := c.Entries[file]
+	c.mu.Unlock()
+	if !ok || entry.Dynamic || entry.Input != input || entry.Config != c.config {
+		return false
+	}
+	for ext, outputFilename := range outputs {
+		hash, ok := entry.Outputs[ext]
+		if !ok {
+			return false
+		}
+		output, err := os.ReadFile(filepath.Clean(outputFilename))
+		if err != nil || rei.Sha256(output) != hash {
+			return false
+		}
+	}
+	c.mu.Lock()
+	c.hits++
+	c.mu.Unlock()
+	return true
+}
+
+// Record remembers the hashes of a freshly built page's output.
+func (c *Cache) Record(file yunyun.RelativePathFile, input string, ext string, output string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.seen[file] = struct{}{}
+	entry, ok := c.Entries[file]
+	if !ok || entry.Input != input || entry.Config != c.config {
+		entry = Entry{Input: input, Config: c.config, Outputs: make(map[string]string)}
+	}
+	entry.Outputs[ext] = output
+	c.Entries[file] = entry
+}
+
+// FreshContext returns true if the fresh page was built with the same
+// context, otherwise the page is no longer a hit and has to be built again.
+func (c *Cache) FreshContext(file yunyun.RelativePathFile, context string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Entries[file].Context == context {
+		return true
+	}
+	c.hits--
+	return false
+}
+
+// RecordContext remembers the context the recorded page was built with.
+func (c *Cache) RecordContext(file yunyun.RelativePathFile, context string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.Entries[file]; ok {
+		entry.Context = context
+		c.Entries[file] = entry
+	}
+}
+
+// MarkDynamic marks the recorded page as dependent on other pages, so
+// it's rebuilt every time.
+func (c *Cache) MarkDynamic(file yunyun.RelativePathFile) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.Entries[file]; ok {
+		entry.Dynamic = true
+		c.Entries[file] = entry
+	}
+}
+
+// Dynamic returns the pages that depend on other pages.
+func (c *Cache) Dynamic() []yunyun.RelativePathFile {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	dynamic := make([]yunyun.RelativePathFile, 0, 4)
+	for file, entry := range c.Entries {
+		if entry.Dynamic {
+			dynamic = append(dynamic, file)
+		}
+	}
+	return dynamic
+}
+
+// Hits returns the number of pages that were not rebuilt.
+func (c *Cache) Hits() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits
+}
+
+// Save writes the cache, pages that weren't a part of the build
+// are forgotten, unless partial is true. Pages whose sources are gone
+// are always forgotten, and their outputs are removed.
+func (c *Cache) Save(partial bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	errs := c.prune()
+	if !partial {
+		for file := range c.Entries {
+			if _, ok := c.seen[file]; !ok {
+				delete(c.Entries, file)
+			}
+		}
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return errors.Join(append(errs, fmt.Errorf("encoding cache: %v", err))...)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return errors.Join(append(errs, fmt.Errorf("creating cache directory: %v", err))...)
+	}
+	if err := os.WriteFile(c.path, data, 0o644); err != nil {
+		return errors.Join(append(errs, fmt.Errorf("writing cache %s: %v", c.path, err))...)
+	}
+	return errors.Join(errs...)
+}
+
+// prune forgets the pages whose sources no longer exist and removes the
+// outputs they left behind. The caller must hold the lock.
+func (c *Cache) prune() []error {
+	errs := make([]error, 0)
+	for file, entry := range c.Entries {
+		source := c.conf.Runtime.WorkDir.Join(file)
+		if _, err := os.Stat(string(source)); !errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		for ext := range entry.Outputs {
+			output := c.conf.Project.InputFilenameToOutputWith(source, ext)
+			switch err := os.Remove(output); {
+			case err == nil:
+				logger.Print("Removed output of a removed page", "path", output)
+			case !errors.Is(err, os.ErrNotExist):
+				errs = append(errs, fmt.Errorf("removing output %s of removed page %s: %v", output, file, err))
+			}
+		}
+		delete(c.Entries, file)
+	}
+	return errs
+}
+
+// Hash returns the hash that the cache uses for inputs and outputs.
+func Hash(data []byte) string {
+	return rei.Sha256(data)
+}
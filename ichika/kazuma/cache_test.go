@@ -0,0 +1,139 @@
+package kazuma
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/internal/testutil"
+	"github.com/thecsw/darkness/yunyun"
+)
+
+// newProject writes a project with a single built page and returns its
+// config, the page, and the page's output.
+func newProject(t *testing.T) (*alpha.DarknessConfig, yunyun.RelativePathFile, string) {
+	t.Helper()
+	conf := testutil.Project(t, map[string]string{
+		"templates/page.html":  "<html></html>\n",
+		"blog/index.org":       "#+title: Blog\n",
+		"blog/index.html":      "<p>Blog</p>\n",
+		"blog/other/index.org": "#+title: Other\n",
+	})
+	return conf, "blog/index.org", filepath.Join(string(conf.Runtime.WorkDir), "blog", "index.html")
+}
+
+// build records the page as built and saves the cache.
+func build(t *testing.T, conf *alpha.DarknessConfig, file yunyun.RelativePathFile, output string) {
+	t.Helper()
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := Open(conf, false)
+	c.Record(file, "input", ".html", Hash(data))
+	if err := c.Save(false); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFresh(t *testing.T) {
+	conf, file, output := newProject(t)
+	build(t, conf, file, output)
+	outputs := map[string]string{".html": output}
+
+	c := Open(conf, false)
+	if !c.Fresh(file, "input", outputs) {
+		t.Error("unchanged page is not fresh")
+	}
+	if c.Fresh(file, "changed", outputs) {
+		t.Error("page with a changed input is fresh")
+	}
+	if c.Fresh("blog/other/index.org", "input", map[string]string{}) {
+		t.Error("page that was never built is fresh")
+	}
+	if c.Fresh(file, "input", map[string]string{".html": output, ".json": output}) {
+		t.Error("page with a missing output is fresh")
+	}
+	if c.Hits() != 1 {
+		t.Errorf("hits = %d, want 1", c.Hits())
+	}
+
+	if err := os.WriteFile(output, []byte("<p>Edited</p>\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if Open(conf, false).Fresh(file, "input", outputs) {
+		t.Error("page with an edited output is fresh")
+	}
+}
+
+func TestFreshForce(t *testing.T) {
+	conf, file, output := newProject(t)
+	build(t, conf, file, output)
+	if Open(conf, true).Fresh(file, "input", map[string]string{".html": output}) {
+		t.Error("page is fresh with -force")
+	}
+}
+
+func TestFreshFingerprint(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(t *testing.T, conf *alpha.DarknessConfig)
+	}{
+		{"config", func(t *testing.T, conf *alpha.DarknessConfig) {
+			conf.Runtime.ConfigHash += "changed"
+		}},
+		{"templates", func(t *testing.T, conf *alpha.DarknessConfig) {
+			template := filepath.Join(string(conf.Runtime.WorkDir), "templates", "page.html")
+			if err := os.WriteFile(template, []byte("<html><body></body></html>\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conf, file, output := newProject(t)
+			build(t, conf, file, output)
+			test.change(t, conf)
+			if Open(conf, false).Fresh(file, "input", map[string]string{".html": output}) {
+				t.Errorf("page is fresh after changing the %s", test.name)
+			}
+		})
+	}
+}
+
+func TestFreshContext(t *testing.T) {
+	conf, file, output := newProject(t)
+	build(t, conf, file, output)
+	c := Open(conf, false)
+	c.RecordContext(file, "neighbours")
+	if !c.Fresh(file, "input", map[string]string{".html": output}) || !c.FreshContext(file, "neighbours") {
+		t.Error("page with the same context is not fresh")
+	}
+	if c.FreshContext(file, "new neighbours") {
+		t.Error("page with a new context is fresh")
+	}
+	if c.Hits() != 0 {
+		t.Errorf("hits = %d, want 0 after the context changed", c.Hits())
+	}
+}
+
+func TestSavePrunesRemovedPages(t *testing.T) {
+	for _, partial := range []bool{false, true} {
+		conf, file, output := newProject(t)
+		build(t, conf, file, output)
+		if err := os.Remove(string(conf.Runtime.WorkDir.Join(file))); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := Open(conf, false).Save(partial); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(output); !os.IsNotExist(err) {
+			t.Errorf("partial %t: output of the removed page is left behind", partial)
+		}
+		if _, ok := Open(conf, false).Entries[file]; ok {
+			t.Errorf("partial %t: removed page is still cached", partial)
+		}
+	}
+}
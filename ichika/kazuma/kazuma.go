@@ -0,0 +1,41 @@
+package kazuma
+
+import (
+	"os"
+	"path/filepath"
+	"runtime/debug"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/emilia/puck"
+	"github.com/thecsw/darkness/yunyun"
+	"github.com/thecsw/rei"
+)
+
+// logger is the logger for kazuma.
+var logger = puck.NewLogger("Kazuma 🗡️ ")
+
+// fingerprint returns the hash of everything besides the page itself that
+// shapes its output: the config, the templates, and the darkness binary.
+func fingerprint(conf *alpha.DarknessConfig) string {
+	binary := "unknown"
+	if info, ok := debug.ReadBuildInfo(); ok {
+		binary = info.Main.Version
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" || setting.Key == "vcs.modified" {
+				binary += setting.Value
+			}
+		}
+	}
+	templates := ""
+	dir := string(conf.Runtime.WorkDir.Join(yunyun.RelativePathFile(conf.Project.Templates)))
+	if files, err := filepath.Glob(filepath.Join(dir, "*.html")); err == nil {
+		for _, file := range files {
+			data, err := os.ReadFile(filepath.Clean(file))
+			if err != nil {
+				continue
+			}
+			templates += file + rei.Sha256(data)
+		}
+	}
+	return rei.Sha256([]byte(conf.Runtime.ConfigHash + templates + binary))
+}
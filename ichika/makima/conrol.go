@@ -1,6 +1,7 @@
 package makima
 
 import (
+	"crypto/sha256"
 	"fmt"
 	"io"
 	"os"
@@ -9,6 +10,8 @@ import (
 	"github.com/thecsw/darkness/emilia/alpha"
 	"github.com/thecsw/darkness/export"
 	"github.com/thecsw/darkness/ichika/chiho"
+	"github.com/thecsw/darkness/ichika/kazuma"
+	"github.com/thecsw/darkness/ichika/misa"
 	"github.com/thecsw/darkness/parse"
 	"github.com/thecsw/darkness/yunyun"
 )
@@ -19,7 +22,7 @@ type Control struct {
 	Conf *alpha.DarknessConfig
 	// Parser is the parser to use for the site.
 	Parser parse.Parser
-	// Exporter is the exporter to use for the site.
+	// Exporter is the exporter to use for the site, set by `For`.
 	Exporter export.Exporter
 
 	// InputFilename is the filename of the input file.
@@ -30,10 +33,24 @@ type Control struct {
 	// Page is the parsed page.
 	Page *yunyun.Page
 
+	// OutputExtension is the extension of the output, set by `For`.
+	OutputExtension string
 	// OutputFilename is the filename of the output file.
 	OutputFilename string
 	// Output is the output file's contents.
 	Output io.Reader
+
+	// Cache is the build cache, nil disables caching.
+	Cache *kazuma.Cache
+	// Cached is true if the page hasn't changed since the last build.
+	Cached bool
+	// KeepPage parses the page even if it's cached, for when the page
+	// is needed after the build, like for feeds.
+	KeepPage bool
+	// inputHash is the hash of the input file's contents.
+	inputHash string
+	// context is the hash of what the page takes from other pages.
+	context string
 }
 
 // Read reads the input file and returns the Control.
@@ -43,30 +60,94 @@ func (c *Control) Read() (Woof, error) {
 		return nil, fmt.Errorf("reading input file %s: %v", c.InputFilename, err)
 	}
 	c.Input = string(file)
+	if c.Cache != nil {
+		outputs := make(map[string]string, len(c.Conf.Project.Outputs))
+		for _, output := range c.Conf.Project.Outputs {
+			outputs[output] = c.Conf.Project.InputFilenameToOutputWith(c.InputFilename, output)
+		}
+		c.inputHash = kazuma.Hash(file)
+		c.Cached = c.Cache.Fresh(c.Conf.Runtime.WorkDir.Rel(c.InputFilename), c.inputHash, outputs)
+	}
 	return c, nil
 }
 
-// Parse parses the input file and returns the Control.
+// Parse parses and enriches the input file and returns the Control.
 func (c *Control) Parse() Woof {
-	c.Page = c.Parser.Do(c.Conf.Runtime.WorkDir.Rel(c.InputFilename), c.Input)
+	if c.Cached && !c.KeepPage {
+		return c
+	}
+	c.Page = chiho.EnrichPage(c.Conf, c.Parser.Do(c.Conf.Runtime.WorkDir.Rel(c.InputFilename), c.Input))
 	return c
 }
 
+// Parsed returns the parsed page, nil if it wasn't parsed.
+func (c *Control) Parsed() *yunyun.Page {
+	return c.Page
+}
+
+// Context sets the hash of what the page takes from other pages, like its
+// neighbours, a cached page is built again if it's not the same as before.
+func (c *Control) Context(context string) {
+	c.context = context
+	if c.Cached && c.Cache != nil {
+		c.Cached = c.Cache.FreshContext(c.Conf.Runtime.WorkDir.Rel(c.InputFilename), context)
+	}
+}
+
+// Use sets the page to export instead of the parsed one, like a listing
+// built on a copy of the parsed page, which stays as it was.
+func (c *Control) Use(page *yunyun.Page) {
+	c.Page = page
+}
+
+// For returns a copy of the Control that exports the shared page into the
+// given output, so one parsed page can be exported into many formats.
+func (c *Control) For(ext string, exporter export.Exporter) Woof {
+	output := *c
+	output.Exporter = exporter
+	output.OutputExtension = ext
+	output.OutputFilename = c.Conf.Project.InputFilenameToOutputWith(c.InputFilename, ext)
+	return &output
+}
+
 // Export exports the parsed page and returns the Control.
 func (c *Control) Export() Woof {
-	c.OutputFilename = c.Conf.Project.InputFilenameToOutput(c.InputFilename)
-	c.Output = c.Exporter.Do(chiho.EnrichPage(c.Conf, c.Page))
+	if c.Cached {
+		return c
+	}
+	c.Output = c.Exporter.Do(c.Page)
 	return c
 }
 
 // Write copies the exported contents onto the output file.
 func (c *Control) Write() error {
+	if c.Cached {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(c.OutputFilename), 0o755); err != nil {
+		return fmt.Errorf("creating output directory for %s: %v", c.OutputFilename, err)
+	}
 	file, err := os.Create(c.OutputFilename)
 	if err != nil {
 		return fmt.Errorf("creating output file %s: %v", c.OutputFilename, err)
 	}
-	if _, err := io.Copy(file, c.Output); err != nil {
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(file, hash), c.Output); err != nil {
+		file.Close()
 		return fmt.Errorf("writing to output file %s: %v", c.OutputFilename, err)
 	}
+	// Writes might only fail when closing, so only closed files are cached.
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("closing output file %s: %v", c.OutputFilename, err)
+	}
+	if c.Cache != nil {
+		file := c.Conf.Runtime.WorkDir.Rel(c.InputFilename)
+		c.Cache.Record(file, c.inputHash, c.OutputExtension, fmt.Sprintf("%x", hash.Sum(nil)))
+		c.Cache.RecordContext(file, c.context)
+		// Pages that list other pages change with them.
+		if misa.Dynamic(c.Page) {
+			c.Cache.MarkDynamic(file)
+		}
+	}
 	return nil
 }
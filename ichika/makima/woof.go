@@ -1,10 +1,23 @@
 package makima
 
+import (
+	"github.com/thecsw/darkness/export"
+	"github.com/thecsw/darkness/yunyun"
+)
+
 // Woof is the interface that wraps the basic methods of a makima parser.
 type Woof interface {
 	Read() (Woof, error)
 	// Parse parses the input internally.
 	Parse() Woof
+	// Parsed returns the parsed page.
+	Parsed() *yunyun.Page
+	// Context sets the hash of what the page takes from other pages.
+	Context(context string)
+	// Use sets the page to export instead of the parsed one.
+	Use(page *yunyun.Page)
+	// For returns a copy that exports into the given output.
+	For(ext string, exporter export.Exporter) Woof
 	// Export exports the result internally.
 	Export() Woof
 	// Write flushes the exported data.
@@ -48,15 +48,37 @@ func CleanCommandFunc() {
 
 // removeOutputFiles is the low-level command to be used when cleaning data.
 func removeOutputFiles(conf *alpha.DarknessConfig) {
-	inputFilenames := hizuru.FindFilesByExtSimple(conf)
-	for _, inputFilename := range inputFilenames {
-		toRemove := conf.Project.InputFilenameToOutput(inputFilename)
-		if err := os.Remove(toRemove); err != nil && !os.IsNotExist(err) {
-			fmt.Println(toRemove, "failed to blow up!!")
+	// With a separate output directory, everything in it is ours.
+	if conf.Project.HasOutputDirectory() {
+		toRemove := string(conf.Project.OutputRoot())
+		// Never blow up the project itself or anything outside of it.
+		if !conf.Runtime.WorkDir.Contains(toRemove) {
+			fmt.Println(toRemove, "is not inside the project, refusing to blow it up!!")
+			return
+		}
+		if err := os.RemoveAll(toRemove); err != nil {
+			fmt.Println(toRemove, "failed to blow up!!", err)
+			return
 		}
 		if !isQuietMegumin {
 			fmt.Println(toRemove, "went boom!")
-			time.Sleep(50 * time.Millisecond)
+		}
+		return
+	}
+	inputFilenames := hizuru.FindFilesByExtSimple(conf)
+	for _, inputFilename := range inputFilenames {
+		for _, toRemove := range conf.Project.InputFilenameToOutputs(inputFilename) {
+			if err := os.Remove(toRemove); err != nil {
+				// Outputs that were never built are fine.
+				if !os.IsNotExist(err) {
+					fmt.Println(toRemove, "failed to blow up!!", err)
+				}
+				continue
+			}
+			if !isQuietMegumin {
+				fmt.Println(toRemove, "went boom!")
+				time.Sleep(50 * time.Millisecond)
+			}
 		}
 	}
 }
@@ -0,0 +1,40 @@
+package ichika
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/thecsw/darkness/internal/testutil"
+	"github.com/thecsw/darkness/yunyun"
+)
+
+func TestCleanKeepsProjectAndParent(t *testing.T) {
+	isQuietMegumin = true
+	for _, outputDir := range []string{".", "./", "..", "public/.."} {
+		t.Run(outputDir, func(t *testing.T) {
+			conf := testutil.Project(t, map[string]string{
+				"darkness.toml": "url = \"https://example.com\"\n\n[project]\noutput_dir = \"public\"\n",
+				"index.org":     "#+title: Home\n",
+			})
+			dir := string(conf.Runtime.WorkDir)
+			files := []string{
+				filepath.Join(filepath.Dir(dir), "neighbour.txt"),
+				filepath.Join(dir, "darkness.toml"),
+				filepath.Join(dir, "index.org"),
+			}
+			if err := os.WriteFile(files[0], []byte("next door\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			// The config would refuse it, so pretend it slipped through.
+			conf.Project.OutputDirectory = yunyun.RelativePathDir(outputDir)
+
+			removeOutputFiles(conf)
+			for _, file := range files {
+				if _, err := os.Stat(file); err != nil {
+					t.Errorf("%s is gone after cleaning with output_dir %q", file, outputDir)
+				}
+			}
+		})
+	}
+}
@@ -20,6 +20,9 @@ func MisaCommandFunc() {
 	addHolosceneTitles := misaCmd.Bool("holoscene-titles", false, "add holoscene titles")
 	rss := misaCmd.String("rss", "", "generate an rss file")
 	rssDirectories := misaCmd.String("rss-dirs", "", "look up specific dirs")
+	atom := misaCmd.String("atom", "", "generate an atom file (uses -rss-dirs)")
+	jsonFeed := misaCmd.String("json-feed", "", "generate a json feed file (uses -rss-dirs)")
+	feeds := misaCmd.Bool("feeds", false, "generate all the feeds from [[rss.feeds]]")
 	dryRun := misaCmd.Bool("dry-run", false, "skip writing files (but do the reading)")
 
 	options := getAlphaOptions(misaCmd)
@@ -27,7 +30,7 @@ func MisaCommandFunc() {
 
 	puck.Logger.SetPrefix("Misa 🍎 ")
 
-	if len(*rss) > 0 {
+	if len(*rss) > 0 || len(*atom) > 0 || len(*jsonFeed) > 0 || *feeds {
 		options.Dev = false
 	}
 	conf := alpha.BuildConfig(options)
@@ -45,7 +48,26 @@ func MisaCommandFunc() {
 		os.Exit(0)
 	}
 	if len(*rss) > 0 {
-		misa.GenerateRssFeed(conf, *rss, strings.Split(*rssDirectories, ","), *dryRun)
+		if err := misa.GenerateRssFeed(conf, *rss, strings.Split(*rssDirectories, ","), *dryRun); err != nil {
+			puck.Logger.Fatalf("generating rss feed: %v", err)
+		}
+	}
+	if len(*atom) > 0 {
+		if err := misa.GenerateAtomFeed(conf, *atom, strings.Split(*rssDirectories, ","), *dryRun); err != nil {
+			puck.Logger.Fatalf("generating atom feed: %v", err)
+		}
+	}
+	if len(*jsonFeed) > 0 {
+		if err := misa.GenerateJsonFeed(conf, *jsonFeed, strings.Split(*rssDirectories, ","), *dryRun); err != nil {
+			puck.Logger.Fatalf("generating json feed: %v", err)
+		}
+	}
+	if *feeds {
+		if err := misa.GenerateFeeds(conf, *dryRun); err != nil {
+			puck.Logger.Fatalf("generating feeds: %v", err)
+		}
+	}
+	if len(*rss) > 0 || len(*atom) > 0 || len(*jsonFeed) > 0 || *feeds {
 		os.Exit(0)
 	}
 
@@ -0,0 +1,129 @@
+package misa
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"time"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/emilia/puck"
+	"github.com/thecsw/darkness/yunyun"
+	"github.com/thecsw/darkness/yunyun/atom"
+)
+
+const (
+	// atomGenerator is the generator name used in the Atom feed.
+	atomGenerator = "Darkness"
+	// atomGeneratorUri is the generator uri used in the Atom feed.
+	atomGeneratorUri = "https://sandyuraz.com/darkness"
+)
+
+// GenerateAtomFeed generates an Atom feed based on the given config and directories.
+func GenerateAtomFeed(conf *alpha.DarknessConfig, atomFilename string, atomDirectories []string, dryRun bool) error {
+	return writeAtomFeed(conf, collectFeed(conf, atomFilename, atomDirectories), dryRun)
+}
+
+// writeAtomFeed writes the feed as Atom.
+func writeAtomFeed(conf *alpha.DarknessConfig, f *feed, dryRun bool) error {
+	pages := f.dated
+
+	// Create Atom entries.
+	entries := make([]atom.Entry, 0, len(pages))
+	// The feed was updated when any of its entries were.
+	updated := time.Time{}
+
+	func() {
+		defer puck.Stopwatch("Built Atom pages", "num", len(pages)).Record()
+		for _, page := range pages {
+			link := conf.Url + string(page.Location)
+			published := mustDate(page).Format(atom.AtomFormat)
+			modified, _ := getUpdated(page)
+			if modified.After(updated) {
+				updated = modified
+			}
+			description := yunyun.FancyText(getDescription(page, conf.Website.DescriptionLength*4))
+			categoryName, categoryLocation := f.category(page)
+			categoryName = yunyun.RemoveFormatting(yunyun.FancyText(categoryName))
+			categories := []atom.Category{{Term: categoryName, Scheme: conf.Url + string(categoryLocation), Label: categoryName}}
+			for _, tag := range page.Tags {
+				categories = append(categories, atom.Category{Term: tag, Scheme: tagLink(conf, tag), Label: tag})
+			}
+
+			// Embed the whole page if asked to, otherwise point to it.
+			content := fmt.Sprintf(`<p>%s</p><p><a href="%s">Continue reading...</a></p>`, html.EscapeString(description), link)
+			if conf.RSS.FullContent {
+				content = fullContent(conf, page)
+			}
+
+			authors := []atom.Person{}
+			if author := pageAuthor(conf, page); len(author) > 0 {
+				authors = append(authors, atom.Person{Name: author})
+			}
+
+			// Attach the media file, if there is one.
+			links := []atom.Link{{Href: link, Rel: atom.LinkRelAlternate, Type: "text/html"}}
+			if enclosure := resolveEnclosure(conf, page); enclosure != nil {
+				links = append(links, atom.Link{
+					Href: enclosure.url, Rel: atom.LinkRelEnclosure, Type: enclosure.mimeType, Length: enclosure.length,
+				})
+			}
+
+			entries = append(entries, atom.Entry{
+				Id:         link,
+				Title:      &atom.Text{Type: atom.TextTypeText, Value: yunyun.RemoveFormatting(yunyun.FancyText(page.Title))},
+				Updated:    modified.Format(atom.AtomFormat),
+				Published:  published,
+				Authors:    authors,
+				Links:      links,
+				Categories: categories,
+				Summary:    &atom.Text{Type: atom.TextTypeText, Value: description},
+				Content:    &atom.Text{Type: atom.TextTypeHtml, Value: content},
+			})
+		}
+	}()
+
+	// Feeds without entries were updated now.
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+
+	// The feed needs an author, the site's author or at least the site itself.
+	author := atom.Person{Name: conf.Author.Name, Uri: conf.Url}
+	if len(author.Name) < 1 {
+		author.Name = conf.Title
+	}
+	if conf.Author.EmailEnable {
+		author.Email = conf.Author.Email
+	}
+
+	// Create the final feed.
+	feed := &atom.Feed{
+		Namespace: atom.AtomNamespace,
+		Lang:      feedLanguage(conf),
+		Id:        conf.Url + f.filename,
+		Title:     &atom.Text{Type: atom.TextTypeText, Value: yunyun.FancyText(f.title)},
+		Subtitle:  &atom.Text{Type: atom.TextTypeText, Value: yunyun.FancyText(f.description)},
+		Updated:   updated.Format(atom.AtomFormat),
+		Authors:   []atom.Person{author},
+		Links: []atom.Link{
+			{Href: conf.Url + f.filename, Rel: atom.LinkRelSelf, Type: "application/atom+xml"},
+			{Href: f.link, Rel: atom.LinkRelAlternate, Type: "text/html"},
+		},
+		Generator: &atom.Generator{Value: atomGenerator, Uri: atomGeneratorUri},
+		Entries:   entries,
+	}
+	if len(conf.RSS.Copyright) > 0 {
+		feed.Rights = &atom.Text{Type: atom.TextTypeText, Value: conf.RSS.Copyright}
+	}
+
+	return writeOutput(conf, f.filename, dryRun, func(w io.Writer) error {
+		if _, err := io.WriteString(w, xml.Header); err != nil {
+			return err
+		}
+		encoder := xml.NewEncoder(w)
+		encoder.Indent("", "  ")
+		return encoder.Encode(feed)
+	})
+}
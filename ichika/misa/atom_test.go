@@ -0,0 +1,66 @@
+package misa
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/thecsw/darkness/yunyun"
+	"github.com/thecsw/darkness/yunyun/atom"
+)
+
+func TestAtomUpdatedFromPages(t *testing.T) {
+	conf := testConfig(t)
+
+	page := func(location yunyun.RelativePathDir, date, updated string) *yunyun.Page {
+		page := yunyun.NewPage(
+			yunyun.WithFilename(yunyun.RelativePathFile(filepath.Join(string(location), "index.org"))),
+			yunyun.WithLocation(location),
+		)
+		page.Title, page.Date, page.Updated = string(location), date, updated
+		// A fresh checkout touches every source file.
+		path := string(conf.Runtime.WorkDir.Join(page.File))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("#+date: "+date+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return page
+	}
+	pages := Pages{
+		page("old", "10; 12020 H.E.", ""),
+		page("edited", "10; 12021 H.E.", "20; 12022 H.E."),
+		// Updates before the page's date are mistakes.
+		page("backdated", "30; 12021 H.E.", "1; 12021 H.E."),
+	}
+	if err := writeAtomFeed(conf, newFeed(conf, "feed.xml", pages), false); err != nil {
+		t.Fatal(err)
+	}
+
+	feed := &atom.Feed{}
+	if err := xml.Unmarshal([]byte(readOutput(t, conf, "feed.xml")), feed); err != nil {
+		t.Fatal(err)
+	}
+	date := func(day, year int) string {
+		return time.Date(year, time.January, day, 0, 0, 0, 0, time.Local).Format(atom.AtomFormat)
+	}
+	want := map[string]string{
+		"https://example.com/old":       date(10, 2020),
+		"https://example.com/edited":    date(20, 2022),
+		"https://example.com/backdated": date(30, 2021),
+	}
+	for _, entry := range feed.Entries {
+		if entry.Updated != want[entry.Id] {
+			t.Errorf("entry %s updated = %s, want %s", entry.Id, entry.Updated, want[entry.Id])
+		}
+	}
+	if len(feed.Entries) != len(want) {
+		t.Errorf("got %d entries, want %d", len(feed.Entries), len(want))
+	}
+	if feed.Updated != date(20, 2022) {
+		t.Errorf("feed updated = %s, want the latest entry update %s", feed.Updated, date(20, 2022))
+	}
+}
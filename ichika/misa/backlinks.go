@@ -0,0 +1,183 @@
+package misa
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/yunyun"
+	"github.com/thecsw/darkness/yunyun/graph"
+)
+
+// Links are the internal links between pages, keyed by the locations
+// of the pages that have them.
+type Links struct {
+	// pages are the linked pages, keyed by their locations.
+	pages map[yunyun.RelativePathDir]*yunyun.Page
+	// outgoing are the pages every page links to.
+	outgoing map[yunyun.RelativePathDir][]yunyun.RelativePathDir
+	// incoming are the pages linking to every page.
+	incoming map[yunyun.RelativePathDir][]yunyun.RelativePathDir
+}
+
+// BuildLinks collects the links of every page and keeps the ones that
+// point to other pages of the website. Drafts are never linked.
+func BuildLinks(conf *alpha.DarknessConfig, pages []*yunyun.Page) *Links {
+	links := &Links{
+		pages:    make(map[yunyun.RelativePathDir]*yunyun.Page, len(pages)),
+		outgoing: make(map[yunyun.RelativePathDir][]yunyun.RelativePathDir),
+		incoming: make(map[yunyun.RelativePathDir][]yunyun.RelativePathDir),
+	}
+	for _, page := range pages {
+		if !page.Accoutrement.Draft.IsEnabled() {
+			links.pages[page.Location] = page
+		}
+	}
+	for _, page := range links.pages {
+		seen := make(map[yunyun.RelativePathDir]bool)
+		for _, link := range PageLinks(page) {
+			target, internal := ResolveLink(conf, page, link)
+			if !internal || target == page.Location || seen[target] {
+				continue
+			}
+			if _, found := links.pages[target]; !found {
+				continue
+			}
+			seen[target] = true
+			links.outgoing[page.Location] = append(links.outgoing[page.Location], target)
+			links.incoming[target] = append(links.incoming[target], page.Location)
+		}
+	}
+	// Links are found in whatever order the pages were built, keep them stable.
+	for _, locations := range links.outgoing {
+		sort.Slice(locations, func(i, j int) bool { return locations[i] < locations[j] })
+	}
+	for _, locations := range links.incoming {
+		sort.Slice(locations, func(i, j int) bool { return locations[i] < locations[j] })
+	}
+	return links
+}
+
+// Apply sets the page's backlinks, if it has any.
+func (l *Links) Apply(page *yunyun.Page) {
+	page.Backlinks = nil
+	for _, location := range l.incoming[page.Location] {
+		page.Backlinks = append(page.Backlinks, yunyun.Neighbour{
+			Title:    l.pages[location].Title,
+			Location: location,
+		})
+	}
+}
+
+// Graph returns the graph of all the links.
+func (l *Links) Graph(conf *alpha.DarknessConfig) *graph.Graph {
+	locations := make([]yunyun.RelativePathDir, 0, len(l.pages))
+	for location := range l.pages {
+		locations = append(locations, location)
+	}
+	sort.Slice(locations, func(i, j int) bool { return locations[i] < locations[j] })
+
+	result := &graph.Graph{
+		Nodes: make([]graph.Node, 0, len(locations)),
+		Links: make([]graph.Link, 0, len(l.outgoing)),
+	}
+	for _, location := range locations {
+		result.Nodes = append(result.Nodes, graph.Node{
+			Id:    string(location),
+			Title: yunyun.RemoveFormatting(l.pages[location].Title),
+			Url:   string(conf.Runtime.Join(yunyun.RelativePathFile(location))),
+		})
+		for _, target := range l.outgoing[location] {
+			result.Links = append(result.Links, graph.Link{Source: string(location), Target: string(target)})
+		}
+	}
+	return result
+}
+
+// WriteGraph writes the graph of all the links.
+func WriteGraph(conf *alpha.DarknessConfig, links *Links, dryRun bool) error {
+	return writeOutput(conf, string(conf.Backlinks.Graph), dryRun, func(w io.Writer) error {
+		return json.NewEncoder(w).Encode(links.Graph(conf))
+	})
+}
+
+// PageLinks returns the links of the page's paragraphs, link blocks,
+// lists, and tables, as they were written.
+func PageLinks(page *yunyun.Page) []string {
+	links := make([]string, 0, 8)
+	add := func(text string) {
+		for _, link := range yunyun.ExtractLinks(text) {
+			links = append(links, link.Link)
+		}
+	}
+	for _, content := range page.Contents {
+		switch {
+		case content.IsParagraph():
+			add(content.Paragraph)
+		case content.IsLink():
+			links = append(links, content.Link)
+		case content.IsList() || content.IsListNumbered():
+			for _, item := range content.List {
+				add(item.Text)
+			}
+		case content.IsTable():
+			for _, row := range content.Table {
+				for _, cell := range row {
+					add(cell)
+				}
+			}
+		}
+	}
+	return links
+}
+
+// ResolveLink returns the location that the link on the page points to,
+// and false if it points outside of the website. Relative links are
+// resolved from the page's directory, fragments and queries are dropped,
+// and the rest is unescaped.
+func ResolveLink(conf *alpha.DarknessConfig, page *yunyun.Page, link string) (yunyun.RelativePathDir, bool) {
+	link = strings.TrimSpace(link)
+	if i := strings.IndexAny(link, "#?"); i >= 0 {
+		link = link[:i]
+	}
+	var target string
+	switch {
+	case link == "":
+		// Links to the page's own headings.
+		return page.Location, true
+	case strings.HasPrefix(link, conf.Url):
+		target = strings.TrimPrefix(link, conf.Url)
+	case strings.Contains(link, ":") || strings.HasPrefix(link, "//"):
+		// Other websites, emails, and the like.
+		return "", false
+	case strings.HasPrefix(link, "/"):
+		root := "/"
+		if conf.Runtime.UrlPath != nil {
+			root = strings.TrimSuffix(conf.Runtime.UrlPath.Path, "/") + "/"
+		}
+		if !strings.HasPrefix(link+"/", root) {
+			return "", false
+		}
+		target = strings.TrimPrefix(link, root)
+	default:
+		target = path.Join(string(page.Location), link)
+	}
+
+	// Links are urls, files on disk are not escaped.
+	if unescaped, err := url.PathUnescape(target); err == nil {
+		target = unescaped
+	}
+	target = path.Clean("/" + target)[1:]
+	// Links to the exported files point to their pages.
+	if strings.HasPrefix(path.Base(target), "index.") {
+		target = path.Dir(target)
+	}
+	if target == "" || target == "/" {
+		target = "."
+	}
+	return yunyun.RelativePathDir(target), true
+}
@@ -0,0 +1,278 @@
+package misa
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/emilia/rem"
+	"github.com/thecsw/darkness/yunyun"
+)
+
+const (
+	// checkWorkers is how many external links are checked at once.
+	checkWorkers = 8
+	// checkTimeout is how long an external link has to answer.
+	checkTimeout = 10 * time.Second
+)
+
+// Problem is a link that points to nothing.
+type Problem struct {
+	// File is the source file of the page with the link.
+	File yunyun.RelativePathFile
+	// Line is the approximate line of the link, 0 if it wasn't found.
+	Line int
+	// Kind is what the link is, like a link, an image, or a preview.
+	Kind string
+	// Target is the link as it was written.
+	Target string
+	// Reason is why the link is broken.
+	Reason string
+}
+
+// String returns the problem the way compilers report them.
+func (p Problem) String() string {
+	return fmt.Sprintf("%s:%d: broken %s %s (%s)", p.File, p.Line, p.Kind, p.Target, p.Reason)
+}
+
+// target is a single link found on a page.
+type target struct {
+	page *yunyun.Page
+	kind string
+	link string
+	// dir is where relative links are looked up, relative to the page.
+	dir yunyun.RelativePathDir
+}
+
+// Check finds every link, image, gallery item, media file, and preview
+// of the pages that points to a nonexistent page or file. External links
+// are only checked if the config asks for it.
+func Check(conf *alpha.DarknessConfig, pages []*yunyun.Page) []Problem {
+	locations := make(map[yunyun.RelativePathDir]bool, len(pages))
+	// Auto indices are listings, which may continue on generated pages.
+	listings := make(map[yunyun.RelativePathDir]bool)
+	for _, page := range pages {
+		locations[page.Location] = true
+		if page.Accoutrement.AutoIndex.IsEnabled() {
+			listings[page.Location] = true
+		}
+	}
+
+	problems := make([]Problem, 0, 8)
+	external := make(map[string][]target)
+	lines := make(map[yunyun.RelativePathFile][]string)
+	report := func(found target, reason string) {
+		file := found.page.File
+		if _, read := lines[file]; !read {
+			data, _ := os.ReadFile(string(conf.Runtime.WorkDir.Join(file)))
+			lines[file] = strings.Split(string(data), "\n")
+		}
+		problems = append(problems, Problem{
+			File:   file,
+			Line:   findLine(lines[file], found.link),
+			Kind:   found.kind,
+			Target: found.link,
+			Reason: reason,
+		})
+	}
+
+	for _, page := range pages {
+		for _, found := range checkTargets(page) {
+			link := strings.TrimSpace(found.link)
+			if strings.HasPrefix(link, "http://") || strings.HasPrefix(link, "https://") {
+				if !strings.HasPrefix(link, conf.Url) {
+					external[link] = append(external[link], found)
+					continue
+				}
+			}
+			if found.dir != "" && !strings.HasPrefix(link, "/") {
+				link = path.Join(string(found.dir), link)
+			}
+			location, internal := ResolveLink(conf, page, link)
+			if !internal || locations[location] || isGenerated(conf, listings, location) {
+				continue
+			}
+			if _, err := os.Stat(string(conf.Runtime.WorkDir.Join(yunyun.RelativePathFile(location)))); err != nil {
+				if found.kind == "link" {
+					report(found, "no such page or file")
+				} else {
+					report(found, "no such file")
+				}
+			}
+		}
+	}
+
+	if conf.Check.External {
+		for link, reason := range checkExternal(conf, external) {
+			for _, found := range external[link] {
+				report(found, reason)
+			}
+		}
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].File != problems[j].File {
+			return problems[i].File < problems[j].File
+		}
+		return problems[i].Line < problems[j].Line
+	})
+	return problems
+}
+
+// checkTargets returns all the links of the page along with their kinds.
+func checkTargets(page *yunyun.Page) []target {
+	targets := make([]target, 0, 16)
+	add := func(kind, link string) {
+		if link = strings.TrimSpace(link); link != "" {
+			targets = append(targets, target{page: page, kind: kind, link: link})
+		}
+	}
+	addGallery := func(content *yunyun.Content, link string) {
+		if link = strings.TrimSpace(link); link != "" {
+			targets = append(targets, target{page: page, kind: "gallery item", link: link, dir: content.GalleryPath})
+		}
+	}
+	addText := func(text string) {
+		for _, link := range yunyun.ExtractLinks(text) {
+			add("link", link.Link)
+		}
+	}
+	for _, content := range page.Contents {
+		switch {
+		case content.IsGallery() && content.IsList():
+			for _, line := range content.List {
+				item := rem.NewGalleryItem(page, content, line.Text)
+				addGallery(content, string(item.Item))
+				add("link", item.Link)
+			}
+		case content.IsLink():
+			add(linkKind(content), content.Link)
+		case content.IsParagraph():
+			addText(content.Paragraph)
+		case content.IsList() || content.IsListNumbered():
+			for _, item := range content.List {
+				addText(item.Text)
+			}
+		case content.IsTable():
+			for _, row := range content.Table {
+				for _, cell := range row {
+					addText(cell)
+				}
+			}
+		}
+	}
+	if page.Accoutrement != nil {
+		add("preview", page.Accoutrement.Preview)
+	}
+	if page.Enclosure != nil {
+		add("enclosure", page.Enclosure.Link)
+	}
+	return targets
+}
+
+// linkKind returns what the link block embeds, the same way exporters see it.
+func linkKind(content *yunyun.Content) string {
+	link := strings.TrimSpace(content.Link)
+	switch {
+	case yunyun.ImageExtRegexp.MatchString(link) || strings.Contains(content.Attributes, "image"):
+		return "image"
+	case yunyun.AudioFileExtRegexp.MatchString(link):
+		return "audio"
+	case yunyun.VideoFileExtRegexp.MatchString(link):
+		return "video"
+	}
+	return "link"
+}
+
+// isGenerated returns true if the location is written by the build itself,
+// so it doesn't exist before the site is built. Listings are the locations
+// of the auto indices.
+func isGenerated(conf *alpha.DarknessConfig, listings map[yunyun.RelativePathDir]bool, location yunyun.RelativePathDir) bool {
+	within := func(dir yunyun.RelativePathDir) bool {
+		return location == dir || strings.HasPrefix(string(location), string(dir)+"/")
+	}
+	if (conf.Tags.Enable && within(conf.Tags.Directory)) || (conf.Archive.Enable && within(conf.Archive.Directory)) {
+		return true
+	}
+	// Later pages of paginated auto indices, like `blog/page/2`.
+	number, err := strconv.Atoi(path.Base(string(location)))
+	if err == nil && number > 1 && path.Base(path.Dir(string(location))) == paginationDirectory &&
+		listings[yunyun.RelativePathDir(path.Dir(path.Dir(string(location))))] {
+		return true
+	}
+	return conf.IsGenerated(yunyun.RelativePathFile(location))
+}
+
+// checkExternal requests every external link and returns the broken ones
+// with their reasons. If the config has an endpoint, the links are checked
+// by asking it, with the link in the `url` query parameter.
+func checkExternal(conf *alpha.DarknessConfig, links map[string][]target) map[string]string {
+	client := &http.Client{Timeout: checkTimeout}
+	broken := make(map[string]string)
+	lock := &sync.Mutex{}
+	queue := make(chan string)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < checkWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for link := range queue {
+				if reason := checkUrl(client, conf.Check.Endpoint, link); reason != "" {
+					lock.Lock()
+					broken[link] = reason
+					lock.Unlock()
+				}
+			}
+		}()
+	}
+	for link := range links {
+		queue <- link
+	}
+	close(queue)
+	wg.Wait()
+	return broken
+}
+
+// checkUrl returns why the link is broken, empty if it isn't.
+func checkUrl(client *http.Client, endpoint, link string) string {
+	request := link
+	if endpoint != "" {
+		// The endpoint might have its own query already.
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return fmt.Sprintf("parsing endpoint %s: %v", endpoint, err)
+		}
+		query := u.Query()
+		query.Set("url", link)
+		u.RawQuery = query.Encode()
+		request = u.String()
+	}
+	resp, err := client.Get(request)
+	if err != nil {
+		return err.Error()
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return resp.Status
+	}
+	return ""
+}
+
+// findLine returns the first line (starting at 1) that mentions the link,
+// 0 if none of them do.
+func findLine(lines []string, link string) int {
+	for i, line := range lines {
+		if strings.Contains(line, link) {
+			return i + 1
+		}
+	}
+	return 0
+}
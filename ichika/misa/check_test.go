@@ -0,0 +1,113 @@
+package misa
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/internal/testutil"
+	"github.com/thecsw/darkness/yunyun"
+)
+
+// checkProject writes the files and returns the config along with a page
+// at the root that has the paragraph.
+func checkProject(t *testing.T, files map[string]string, paragraph string) (*alpha.DarknessConfig, *yunyun.Page) {
+	t.Helper()
+	files["index.org"] = paragraph + "\n"
+	conf := testutil.Project(t, files)
+	// Parsers build the markup regexes, here nothing is parsed.
+	yunyun.ActiveMarkings.BuildRegex()
+	page := yunyun.NewPage(
+		yunyun.WithFilename("index.org"),
+		yunyun.WithLocation("."),
+		yunyun.WithContents([]*yunyun.Content{{Type: yunyun.TypeParagraph, Paragraph: paragraph}}),
+	)
+	return conf, page
+}
+
+// targets returns the broken targets of the problems.
+func targets(problems []Problem) []string {
+	broken := make([]string, 0, len(problems))
+	for _, problem := range problems {
+		broken = append(broken, problem.Target)
+	}
+	return broken
+}
+
+func TestCheckInternal(t *testing.T) {
+	conf, page := checkProject(t, map[string]string{
+		"my file.png":    "png",
+		"notes/todo.txt": "todo",
+	}, strings.Join([]string{
+		"[[blog][Blog]]",
+		"[[https://example.com/blog/][Blog again]]",
+		"[[/blog/#top][Blog top]]",
+		"[[#heading][Here]]",
+		"[[my%20file.png][Picture]]",
+		"[[notes/todo.txt][Todo]]",
+		"[[missing][Missing]]",
+		"[[https://example.com/gone][Gone]]",
+		"[[notes/done.txt][Done]]",
+		"[[blog/page/2][Older posts]]",
+		"[[blog/page/first][Not a page number]]",
+		"[[docs/page/2][Not a listing]]",
+	}, " "))
+	blog := yunyun.NewPage(yunyun.WithFilename("blog/index.org"), yunyun.WithLocation("blog"))
+	blog.Accoutrement.AutoIndex.Enable()
+
+	got := targets(Check(conf, []*yunyun.Page{page, blog}))
+	want := []string{"missing", "https://example.com/gone", "notes/done.txt", "blog/page/first", "docs/page/2"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("broken links = %q, want %q", got, want)
+	}
+}
+
+func TestCheckExternal(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ok" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	conf, page := checkProject(t, map[string]string{}, "[["+server.URL+"/ok][Fine]] and [["+server.URL+"/gone][Gone]]")
+	conf.Check.External = true
+
+	problems := Check(conf, []*yunyun.Page{page})
+	if len(problems) != 1 || problems[0].Target != server.URL+"/gone" {
+		t.Fatalf("problems = %v, want only %s/gone", problems, server.URL)
+	}
+	if problems[0].Reason != "404 Not Found" || problems[0].Line != 1 {
+		t.Errorf("problem = %v, want a 404 on line 1", problems[0])
+	}
+}
+
+func TestCheckExternalEndpoint(t *testing.T) {
+	asked := make(chan string, 2)
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("key") != "secret" {
+			http.Error(w, "no key", http.StatusUnauthorized)
+			return
+		}
+		link := r.URL.Query().Get("url")
+		asked <- link
+		if strings.HasSuffix(link, "/gone") {
+			http.NotFound(w, r)
+		}
+	}))
+	defer endpoint.Close()
+
+	conf, page := checkProject(t, map[string]string{}, "[[https://other.example/ok][Fine]] and [[https://other.example/gone][Gone]]")
+	conf.Check.External = true
+	conf.Check.Endpoint = endpoint.URL + "/check?key=secret"
+
+	got := targets(Check(conf, []*yunyun.Page{page}))
+	if len(got) != 1 || got[0] != "https://other.example/gone" {
+		t.Errorf("broken links = %q, want only https://other.example/gone", got)
+	}
+	if len(asked) != 2 {
+		t.Errorf("endpoint was asked about %d links, want 2", len(asked))
+	}
+}
@@ -0,0 +1,93 @@
+package misa
+
+import (
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/emilia/puck"
+	"github.com/thecsw/darkness/yunyun"
+	"github.com/thecsw/darkness/yunyun/rss"
+)
+
+// mediaTypes are the mime types of media files that systems often don't know.
+var mediaTypes = map[string]string{
+	".mp3":  "audio/mpeg",
+	".m4a":  "audio/mp4",
+	".aac":  "audio/aac",
+	".ogg":  "audio/ogg",
+	".opus": "audio/ogg",
+	".wav":  "audio/wav",
+	".flac": "audio/flac",
+	".mp4":  "video/mp4",
+	".m4v":  "video/x-m4v",
+	".mov":  "video/quicktime",
+	".webm": "video/webm",
+	".mkv":  "video/x-matroska",
+}
+
+// enclosure is the page's media file resolved for feeds.
+type enclosure struct {
+	*yunyun.Enclosure
+	// url is the full url of the media file.
+	url string
+	// mimeType is the mime type of the media file.
+	mimeType string
+	// length is the size of the media file in bytes, 0 if unknown.
+	length int64
+}
+
+// resolveEnclosure finds the page's media file, returns nil if there is none.
+// Local files are relative to the page, unless they start with a slash.
+func resolveEnclosure(conf *alpha.DarknessConfig, page *yunyun.Page) *enclosure {
+	if page.Enclosure == nil {
+		return nil
+	}
+	link := page.Enclosure.Link
+	resolved := &enclosure{Enclosure: page.Enclosure, url: link, mimeType: mediaType(link)}
+	if yunyun.UrlRegexp.MatchString(link) {
+		puck.Logger.Warn("Enclosure is remote, its length is unknown", "page", page.File, "url", link)
+		return resolved
+	}
+	relative := path.Join(string(page.Location), link)
+	if strings.HasPrefix(link, "/") {
+		relative = strings.TrimPrefix(path.Clean(link), "/")
+	}
+	resolved.url = conf.Url + relative
+	info, err := os.Stat(string(conf.Runtime.WorkDir.Join(yunyun.RelativePathFile(relative))))
+	if err != nil {
+		puck.Logger.Warn("Enclosure not found, its length is unknown", "page", page.File, "path", relative, "err", err)
+		return resolved
+	}
+	resolved.length = info.Size()
+	return resolved
+}
+
+// mediaType returns the mime type of the media file from its extension.
+func mediaType(link string) string {
+	ext := strings.ToLower(filepath.Ext(link))
+	if mimeType, ok := mediaTypes[ext]; ok {
+		return mimeType
+	}
+	if mimeType := mime.TypeByExtension(ext); len(mimeType) > 0 {
+		return mimeType
+	}
+	return "application/octet-stream"
+}
+
+// rss returns the enclosure and the podcast tags of the rss item.
+func (e *enclosure) rss() (*rss.Enclosure, *rss.Itunes) {
+	if e == nil {
+		return nil, nil
+	}
+	itunes := &rss.Itunes{Duration: e.Duration, Episode: e.Episode}
+	// Only tell whether it's explicit if the page did.
+	if e.Explicit != nil {
+		itunes.Explicit = strconv.FormatBool(*e.Explicit)
+	}
+	return &rss.Enclosure{Url: e.url, Type: e.mimeType, Length: e.length}, itunes
+}
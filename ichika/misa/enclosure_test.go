@@ -0,0 +1,26 @@
+package misa
+
+import (
+	"testing"
+
+	"github.com/thecsw/darkness/yunyun"
+)
+
+func TestEnclosureExplicit(t *testing.T) {
+	tests := []struct {
+		declaration string
+		want        string
+	}{
+		// Undeclared, so the tag is left out.
+		{"episode.mp3 duration=01:02:03", ""},
+		{"episode.mp3 explicit=yes", "true"},
+		{"episode.mp3 explicit=true", "true"},
+		{"episode.mp3 explicit=no", "false"},
+	}
+	for _, test := range tests {
+		_, itunes := (&enclosure{Enclosure: yunyun.ParseEnclosure(test.declaration)}).rss()
+		if itunes.Explicit != test.want {
+			t.Errorf("explicit of %q = %q, want %q", test.declaration, itunes.Explicit, test.want)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package misa
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/emilia/puck"
+	"github.com/thecsw/darkness/export/html"
+	"github.com/thecsw/darkness/ichika/chiho"
+	"github.com/thecsw/darkness/ichika/hizuru"
+	"github.com/thecsw/darkness/yunyun"
+	"github.com/thecsw/gana"
+)
+
+// feed is a feed to generate, whatever the format.
+type feed struct {
+	// filename is where the feed is written, relative to the project.
+	filename string
+	// title is the title of the feed.
+	title string
+	// link is the url of the page that the feed is for.
+	link string
+	// description is the description of the whole feed.
+	description string
+	// all are all the pages found in the feed directories.
+	all Pages
+	// locations are all the pages keyed by their locations, to find categories.
+	locations map[yunyun.RelativePathDir]*yunyun.Page
+	// dated are the published pages with dates, newest first.
+	dated Pages
+}
+
+// collectFeed builds the pages in the directories and picks the ones for a feed.
+func collectFeed(conf *alpha.DarknessConfig, filename string, directories []string) *feed {
+	return newFeed(conf, filename, buildPages(conf, directories))
+}
+
+// buildPages builds and enriches the pages in the directories, so they're
+// the same as the pages that the build exports.
+func buildPages(conf *alpha.DarknessConfig, directories []string) Pages {
+	return gana.Map(func(page *yunyun.Page) *yunyun.Page {
+		return chiho.EnrichPage(conf, page)
+	}, hizuru.BuildPagesSimple(conf, directories))
+}
+
+// newFeed picks the pages for a feed out of the passed pages.
+func newFeed(conf *alpha.DarknessConfig, filename string, allPages Pages) *feed {
+	// Try to retrieve the top root page to get channel description. If not found, use the
+	// website's title as the description.
+	topPage := gana.First(gana.Filter(func(page *yunyun.Page) bool { return page.Location == "." }, allPages))
+	description := conf.RSS.Description
+	if topPage != nil {
+		description = getDescription(topPage, conf.Website.DescriptionLength*4)
+	}
+	// If both the top page and RSS config have no description, default to the title.
+	if len(description) < 1 {
+		description = conf.Title
+	}
+
+	sort.Slice(allPages, func(i, j int) bool { return allPages[i].Title < allPages[j].Title })
+
+	// Get all pages that have dates defined and aren't drafts, we only use those in feeds.
+	pages := Pages(gana.Filter(func(page *yunyun.Page) bool {
+		_, dateFound := getDate(page)
+		return dateFound && !page.Accoutrement.Draft.IsEnabled()
+	}, allPages))
+
+	// Sort the pages in descending order of dates.
+	sort.Sort(pages)
+
+	locations := make(map[yunyun.RelativePathDir]*yunyun.Page, len(allPages))
+	for _, page := range allPages {
+		locations[page.Location] = page
+	}
+
+	return &feed{
+		filename:    filename,
+		title:       conf.Title,
+		link:        conf.Url,
+		description: description,
+		all:         allPages,
+		locations:   locations,
+		dated:       pages,
+	}
+}
+
+// category returns the name and the location of the page's category,
+// which is the page itself if it has no parent page.
+func (f *feed) category(page *yunyun.Page) (string, yunyun.RelativePathDir) {
+	if categoryPage := getCategory(page, f.locations); categoryPage != nil {
+		return categoryPage.Title, categoryPage.Location
+	}
+	return page.Title, page.Location
+}
+
+// linkAttributeRegexp matches html attributes that hold links.
+var linkAttributeRegexp = regexp.MustCompile(`(\s(?:href|src|data-src|poster)=")([^"]*)(")`)
+
+// fullContent returns the whole page exported into html, with all the links
+// made absolute, as feed readers don't know where the page came from.
+func fullContent(conf *alpha.DarknessConfig, page *yunyun.Page) string {
+	body := html.ExporterHtml{Config: conf}.Body(page)
+	return absoluteLinks(conf.Url+string(page.Location)+"/", body)
+}
+
+// absoluteLinks resolves all the links in the html against the base url.
+func absoluteLinks(base, body string) string {
+	baseUrl, err := url.Parse(base)
+	if err != nil {
+		puck.Logger.Warn("Parsing page url, keeping relative links", "url", base, "err", err)
+		return body
+	}
+	return linkAttributeRegexp.ReplaceAllStringFunc(body, func(attribute string) string {
+		matches := linkAttributeRegexp.FindStringSubmatch(attribute)
+		link, err := url.Parse(matches[2])
+		if err != nil {
+			return attribute
+		}
+		return matches[1] + baseUrl.ResolveReference(link).String() + matches[3]
+	})
+}
+
+// writeOutput writes the file into the output directory (or stdout on dry
+// runs) by calling the encode function.
+func writeOutput(conf *alpha.DarknessConfig, filename string, dryRun bool, encode func(io.Writer) error) error {
+	if dryRun {
+		if err := encode(os.Stdout); err != nil {
+			return fmt.Errorf("encoding %s: %w", filename, err)
+		}
+		return nil
+	}
+	target := filepath.Clean(string(conf.Project.SourceToOutput(conf.Runtime.WorkDir.Join(yunyun.RelativePathFile(filename)))))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("creating directory %s: %w", filepath.Dir(target), err)
+	}
+	file, err := os.Create(target)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", target, err)
+	}
+	if err := encode(file); err != nil {
+		file.Close()
+		return fmt.Errorf("encoding %s: %w", target, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("closing %s: %w", target, err)
+	}
+	puck.Logger.Print("Created file", "path", target)
+	return nil
+}
+
+// pageAuthor returns the author of the page to name in feed entries, which
+// is empty for the site's author, who's the feed's author.
+func pageAuthor(conf *alpha.DarknessConfig, page *yunyun.Page) string {
+	if page.Author == conf.Author.Name {
+		return ""
+	}
+	return page.Author
+}
+
+// feedLanguage returns the language of the feeds, falling back to the
+// site's language if the feeds don't have one.
+func feedLanguage(conf *alpha.DarknessConfig) string {
+	if len(conf.RSS.Language) < 1 {
+		return conf.Website.Language
+	}
+	return conf.RSS.Language
+}
+
+// sortPages returns a copy of the pages sorted by their locations, since
+// pages come in whatever order they were built.
+func sortPages(pages []*yunyun.Page) []*yunyun.Page {
+	sorted := append([]*yunyun.Page(nil), pages...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Location < sorted[j].Location })
+	return sorted
+}
+
+// plainText returns the text without formatting and footnotes, which
+// enriched pages have replaced with references.
+func plainText(text string) string {
+	return yunyun.RemoveFormatting(yunyun.FootnotePostProcessingRegexp.ReplaceAllString(text, ""))
+}
@@ -0,0 +1,159 @@
+package misa
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/emilia/puck"
+	"github.com/thecsw/darkness/yunyun"
+	"github.com/thecsw/gana"
+)
+
+const (
+	// feedFormatRss is the format of RSS 2.0 feeds.
+	feedFormatRss = "rss"
+	// feedFormatAtom is the format of Atom 1.0 feeds.
+	feedFormatAtom = "atom"
+	// feedFormatJson is the format of JSON Feed 1.1 feeds.
+	feedFormatJson = "json"
+)
+
+// feedWriters are the functions that write feeds in each format.
+var feedWriters = map[string]func(*alpha.DarknessConfig, *feed, bool) error{
+	feedFormatRss:  writeRssFeed,
+	feedFormatAtom: writeAtomFeed,
+	feedFormatJson: writeJsonFeed,
+}
+
+// GenerateFeeds generates all the feeds declared in `[[rss.feeds]]`.
+func GenerateFeeds(conf *alpha.DarknessConfig, dryRun bool) error {
+	return WriteFeeds(conf, buildPages(conf, nil), dryRun)
+}
+
+// WriteFeeds writes all the feeds declared in `[[rss.feeds]]` out of
+// the passed pages, which should be parsed and enriched. A feed that fails
+// doesn't stop the others, all the failures are returned together.
+func WriteFeeds(conf *alpha.DarknessConfig, pages []*yunyun.Page, dryRun bool) error {
+	var errs []error
+	// Feeds with the same filenames would overwrite each other, the
+	// declared ones come first, so category feeds can't take their place.
+	written := make(map[string]string, len(conf.RSS.Feeds))
+	for _, feedConf := range conf.RSS.Feeds {
+		written[filepath.Clean(feedConf.Filename)] = feedConf.Filename
+	}
+	for _, feedConf := range conf.RSS.Feeds {
+		format := feedFormat(feedConf)
+		write, ok := feedWriters[format]
+		if !ok {
+			puck.Logger.Error("Unknown feed format, skipping", "filename", feedConf.Filename, "format", format)
+			continue
+		}
+		if len(feedConf.Filename) < 1 {
+			puck.Logger.Error("Feed has no filename, skipping", "format", format)
+			continue
+		}
+
+		f := newFeed(conf, feedConf.Filename, pagesIn(pages, feedConf.Directories))
+		if len(feedConf.Title) > 0 {
+			f.title = feedConf.Title
+		}
+		if len(feedConf.Description) > 0 {
+			f.description = feedConf.Description
+		}
+
+		feeds := []*feed{f}
+		if feedConf.Categories {
+			feeds = append(feeds, f.categories(conf)...)
+		}
+		for i, f := range feeds {
+			if other, taken := written[filepath.Clean(f.filename)]; taken && i > 0 {
+				errs = append(errs, fmt.Errorf("category feed %s of %s is already written by %s", f.filename, feedConf.Filename, other))
+				continue
+			}
+			written[filepath.Clean(f.filename)] = feedConf.Filename
+			if feedConf.Limit > 0 && len(f.dated) > feedConf.Limit {
+				f.dated = f.dated[:feedConf.Limit]
+			}
+			if err := write(conf, f, dryRun); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// categoryFeedFilename returns where the category's feed is written. Feeds
+// from other directories keep their directories in the name, so that
+// `feed.xml` and `podcast/feed.xml` become `blog/feed.xml` and
+// `blog/podcast-feed.xml`.
+func categoryFeedFilename(category yunyun.RelativePathDir, filename string) string {
+	name := strings.ReplaceAll(filepath.ToSlash(filepath.Clean(filename)), "/", "-")
+	return filepath.Join(string(category), name)
+}
+
+// pagesIn returns the pages that are in the directories, all of them if none given.
+func pagesIn(pages []*yunyun.Page, directories []string) Pages {
+	found := make(Pages, 0, len(pages))
+	for _, page := range pages {
+		if len(directories) < 1 || gana.Anyf(func(dir string) bool {
+			dir = filepath.Clean(dir)
+			return dir == "." || strings.HasPrefix(string(page.File), dir+string(filepath.Separator))
+		}, directories) {
+			found = append(found, page)
+		}
+	}
+	return found
+}
+
+// feedFormat returns the format of the feed, which is guessed
+// from the filename's extension if not given.
+func feedFormat(feedConf alpha.FeedConfig) string {
+	if len(feedConf.Format) > 0 {
+		return strings.ToLower(feedConf.Format)
+	}
+	switch strings.ToLower(filepath.Ext(feedConf.Filename)) {
+	case ".atom":
+		return feedFormatAtom
+	case ".json":
+		return feedFormatJson
+	default:
+		return feedFormatRss
+	}
+}
+
+// categories splits the feed into a feed for every category, each written
+// in the category's directory. Pages without a category are left out.
+func (f *feed) categories(conf *alpha.DarknessConfig) []*feed {
+	feeds := make([]*feed, 0, 4)
+	byLocation := make(map[yunyun.RelativePathDir]*feed)
+	for _, page := range f.dated {
+		// Top level pages are their own categories.
+		categoryPage := getCategory(page, f.locations)
+		if categoryPage == nil || categoryPage.Location == page.Location {
+			continue
+		}
+		categoryFeed, ok := byLocation[categoryPage.Location]
+		if !ok {
+			categoryFeed = &feed{
+				filename:    categoryFeedFilename(categoryPage.Location, f.filename),
+				title:       f.title + " — " + yunyun.RemoveFormatting(categoryPage.Title),
+				link:        conf.Url + string(categoryPage.Location),
+				description: getDescription(categoryPage, conf.Website.DescriptionLength*4),
+				all:         f.all,
+				locations:   f.locations,
+				dated:       make(Pages, 0, 8),
+			}
+			if len(categoryFeed.description) < 1 {
+				categoryFeed.description = f.description
+			}
+			byLocation[categoryPage.Location] = categoryFeed
+			feeds = append(feeds, categoryFeed)
+		}
+		// Pages are already sorted, so categories stay sorted too.
+		categoryFeed.dated = append(categoryFeed.dated, page)
+	}
+	return feeds
+}
@@ -0,0 +1,133 @@
+package misa
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/ichika/chiho"
+	"github.com/thecsw/darkness/ichika/hizuru"
+	"github.com/thecsw/darkness/yunyun"
+	"github.com/thecsw/gana"
+)
+
+// AutoIndex returns the listing of the page's child pages, newest first,
+// which saves us from maintaining listing pages by hand. The first page is
+// a copy of the passed page with the list appended, long lists continue on
+// the pages after it. Nil if the page has no children.
+func AutoIndex(conf *alpha.DarknessConfig, page *yunyun.Page, pages []*yunyun.Page) []*yunyun.Page {
+	children := make(Pages, 0, 16)
+	for _, child := range pages {
+		if child.Location == page.Location || child.Accoutrement.Draft.IsEnabled() {
+			continue
+		}
+		if yunyun.RelativePathDir(filepath.Dir(string(child.Location))) == page.Location {
+			children = append(children, child)
+		}
+	}
+	if len(children) < 1 {
+		return nil
+	}
+	sortNewestFirst(children)
+
+	items := make([]yunyun.ListItem, 0, len(children))
+	for _, child := range children {
+		item := pageListItem(conf, child)
+		if description := getDescription(child, conf.Website.DescriptionLength); len(description) > 0 {
+			item.Text += " — " + description
+		}
+		items = append(items, item)
+	}
+	return paginate(conf, page, items)
+}
+
+// ArchivePages returns the generated archive pages, which are a page for
+// every month and year, and the archive index, which lists all the years.
+// Only dated pages are archived, drafts are never listed.
+func ArchivePages(conf *alpha.DarknessConfig, pages []*yunyun.Page) []*yunyun.Page {
+	// Pages grouped by years and months, newest first.
+	dated := make(Pages, 0, len(pages))
+	for _, page := range pages {
+		if _, found := getDate(page); found && !page.Accoutrement.Draft.IsEnabled() {
+			dated = append(dated, page)
+		}
+	}
+	sortNewestFirst(dated)
+	years := make([]int, 0, 8)
+	months := make(map[int][]int)
+	byMonth := make(map[[2]int]Pages)
+	for _, page := range dated {
+		date := mustDate(page)
+		year, month := date.Year(), int(date.Month())
+		if _, ok := months[year]; !ok {
+			years = append(years, year)
+		}
+		if _, ok := byMonth[[2]int{year, month}]; !ok {
+			months[year] = append(months[year], month)
+		}
+		byMonth[[2]int{year, month}] = append(byMonth[[2]int{year, month}], page)
+	}
+
+	generated := make([]*yunyun.Page, 0, len(byMonth)+len(years)+1)
+	index := make([]yunyun.ListItem, 0, len(years))
+	for _, year := range years {
+		yearLocation := yunyun.RelativePathDir(filepath.Join(string(conf.Archive.Directory), fmt.Sprintf("%d", year)))
+		contents := make([]*yunyun.Content, 0, 2*len(months[year]))
+		total := 0
+		for _, month := range months[year] {
+			monthPages := byMonth[[2]int{year, month}]
+			monthName := time.Month(month).String()
+			monthLocation := yunyun.RelativePathDir(filepath.Join(string(yearLocation), fmt.Sprintf("%02d", month)))
+			items := make([]yunyun.ListItem, 0, len(monthPages))
+			for _, page := range monthPages {
+				items = append(items, pageListItem(conf, page))
+			}
+			contents = append(contents,
+				&yunyun.Content{
+					Type:         yunyun.TypeHeading,
+					HeadingLevel: 2,
+					Heading:      fmt.Sprintf("[[%s][%s]]", conf.Runtime.Join(yunyun.RelativePathFile(monthLocation)), monthName),
+				},
+				&yunyun.Content{Type: yunyun.TypeList, List: items},
+			)
+			listing := generatedPage(conf, monthLocation, fmt.Sprintf("%s %d", monthName, year))
+			generated = append(generated, paginate(conf, listing, items)...)
+			total += len(monthPages)
+		}
+		generated = append(generated, generatedPage(conf, yearLocation, fmt.Sprintf("%d", year), contents...))
+		index = append(index, yunyun.ListItem{
+			Level: 1,
+			Text:  fmt.Sprintf("[[%s][%d]] (%d)", conf.Runtime.Join(yunyun.RelativePathFile(yearLocation)), year, total),
+		})
+	}
+	generated = append(generated, generatedPage(conf, conf.Archive.Directory, conf.Archive.Title,
+		&yunyun.Content{Type: yunyun.TypeList, List: index},
+	))
+	return generated
+}
+
+// pageListItem returns the list item that links to the page, followed
+// by its date as written.
+func pageListItem(conf *alpha.DarknessConfig, page *yunyun.Page) yunyun.ListItem {
+	text := fmt.Sprintf("[[%s][%s]]", conf.Runtime.Join(yunyun.RelativePathFile(page.Location)), page.Title)
+	if _, found := getDate(page); found {
+		text += ", " + page.Date
+	}
+	return yunyun.ListItem{Level: 1, Text: text}
+}
+
+// AllPages builds and enriches every page of the project, for when the
+// pages weren't kept during the build.
+func AllPages(conf *alpha.DarknessConfig) []*yunyun.Page {
+	return buildPages(conf, nil)
+}
+
+// CompletePages returns every page of the project, the passed pages are
+// used as they are, so only the rest are built and enriched.
+func CompletePages(conf *alpha.DarknessConfig, pages []*yunyun.Page) []*yunyun.Page {
+	missing := gana.Map(func(page *yunyun.Page) *yunyun.Page {
+		return chiho.EnrichPage(conf, page)
+	}, hizuru.BuildPagesMissing(conf, pages))
+	return append(append(make([]*yunyun.Page, 0, len(pages)+len(missing)), pages...), missing...)
+}
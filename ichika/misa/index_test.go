@@ -0,0 +1,56 @@
+package misa
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/thecsw/darkness/export/html"
+	"github.com/thecsw/darkness/yunyun"
+)
+
+func TestAutoIndexListsChildren(t *testing.T) {
+	conf := testConfig(t)
+
+	page := func(location yunyun.RelativePathDir, title string) *yunyun.Page {
+		page := generatedPage(conf, location, title)
+		page.Date = "1; 12023 H.E."
+		return page
+	}
+	parent := generatedPage(conf, "blog", "Blog")
+	pages := []*yunyun.Page{parent, page("blog/first", "First"), page("blog/first/nested", "Nested"), page("notes", "Notes")}
+	listings := AutoIndex(conf, parent, pages)
+	if len(listings) != 1 {
+		t.Fatalf("expected a single page of listings, got %d", len(listings))
+	}
+
+	body := html.ExporterHtml{Config: conf}.Body(listings[0])
+	if !strings.Contains(body, `<li class="l1">`) {
+		t.Errorf("listing items should be top level list items, got\n%s", body)
+	}
+	if !strings.Contains(body, `href="https://example.com/blog/first"`) {
+		t.Errorf("direct child is not listed, got\n%s", body)
+	}
+	if strings.Contains(body, "Nested") || strings.Contains(body, "Notes") {
+		t.Errorf("only direct children should be listed, got\n%s", body)
+	}
+}
+
+func TestAutoIndexKeepsParsedPage(t *testing.T) {
+	conf := testConfig(t)
+	conf.Pagination.Size = 1
+
+	parent := generatedPage(conf, "blog", "Blog")
+	pages := []*yunyun.Page{parent, generatedPage(conf, "blog/first", "First"), generatedPage(conf, "blog/second", "Second")}
+	listings := AutoIndex(conf, parent, pages)
+	if len(listings) != 2 {
+		t.Fatalf("got %d listing pages, want 2", len(listings))
+	}
+	// Search and feeds use the parsed page, which shouldn't have the listing.
+	if len(parent.Contents) != 0 || len(parent.Links) != 0 {
+		t.Errorf("parsed page was changed, contents %+v, links %+v", parent.Contents, parent.Links)
+	}
+	// The navigation between the listing pages is not a description.
+	if description := getDescription(listings[0], conf.Website.DescriptionLength); description != "" {
+		t.Errorf("listing description = %q, want none", description)
+	}
+}
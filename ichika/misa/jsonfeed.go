@@ -0,0 +1,94 @@
+package misa
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/emilia/puck"
+	"github.com/thecsw/darkness/yunyun"
+	"github.com/thecsw/darkness/yunyun/jsonfeed"
+)
+
+// jsonfeedFormat is the date format used in JSON feeds (RFC 3339).
+const jsonfeedFormat = time.RFC3339
+
+// GenerateJsonFeed generates a JSON feed based on the given config and directories.
+func GenerateJsonFeed(conf *alpha.DarknessConfig, jsonFilename string, jsonDirectories []string, dryRun bool) error {
+	return writeJsonFeed(conf, collectFeed(conf, jsonFilename, jsonDirectories), dryRun)
+}
+
+// writeJsonFeed writes the feed as a JSON feed.
+func writeJsonFeed(conf *alpha.DarknessConfig, f *feed, dryRun bool) error {
+	pages := f.dated
+
+	// Create JSON feed items.
+	items := make([]jsonfeed.Item, 0, len(pages))
+
+	func() {
+		defer puck.Stopwatch("Built JSON feed pages", "num", len(pages)).Record()
+		for _, page := range pages {
+			link := conf.Url + string(page.Location)
+			categoryName, _ := f.category(page)
+
+			authors := []jsonfeed.Author{}
+			if author := pageAuthor(conf, page); len(author) > 0 {
+				authors = append(authors, jsonfeed.Author{Name: author})
+			}
+
+			item := jsonfeed.Item{
+				Id:            link,
+				Url:           link,
+				Title:         yunyun.RemoveFormatting(yunyun.FancyText(page.Title)),
+				ContentText:   yunyun.FancyText(getDescription(page, conf.Website.DescriptionLength*4)),
+				DatePublished: mustDate(page).Format(jsonfeedFormat),
+				Authors:       authors,
+				Tags:          append([]string{yunyun.RemoveFormatting(yunyun.FancyText(categoryName))}, page.Tags...),
+			}
+			// Attach the media file, if there is one.
+			if enclosure := resolveEnclosure(conf, page); enclosure != nil {
+				item.Attachments = []jsonfeed.Attachment{{
+					Url:               enclosure.url,
+					MimeType:          enclosure.mimeType,
+					SizeInBytes:       enclosure.length,
+					DurationInSeconds: enclosure.Seconds(),
+				}}
+			}
+			// Embed the whole page if asked to, the description becomes the summary.
+			if conf.RSS.FullContent {
+				item.ContentHtml = fullContent(conf, page)
+				item.Summary, item.ContentText = item.ContentText, ""
+			}
+			items = append(items, item)
+		}
+	}()
+
+	// The feed needs an author, the site's author or at least the site itself.
+	author := jsonfeed.Author{Name: conf.Author.Name, Url: conf.Url}
+	if len(author.Name) < 1 {
+		author.Name = conf.Title
+	}
+	if len(conf.Author.Image) > 0 {
+		author.Avatar = string(conf.Author.ImagePreComputed)
+	}
+
+	// Create the final feed.
+	feed := &jsonfeed.Feed{
+		Version:     jsonfeed.JsonFeedVersion,
+		Title:       yunyun.FancyText(f.title),
+		HomePageUrl: f.link,
+		FeedUrl:     conf.Url + f.filename,
+		Description: yunyun.FancyText(f.description),
+		Authors:     []jsonfeed.Author{author},
+		Language:    feedLanguage(conf),
+		Items:       items,
+	}
+
+	return writeOutput(conf, f.filename, dryRun, func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(feed)
+	})
+}
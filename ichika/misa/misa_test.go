@@ -0,0 +1,30 @@
+package misa
+
+import (
+	"os"
+	"testing"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/internal/testutil"
+	"github.com/thecsw/darkness/yunyun"
+)
+
+// testConfig returns the config of an empty project at https://example.com
+// in a temporary directory.
+func testConfig(t *testing.T) *alpha.DarknessConfig {
+	t.Helper()
+	conf := testutil.Project(t, nil)
+	// Parsers build the markup regexes, here nothing is parsed.
+	yunyun.ActiveMarkings.BuildRegex()
+	return conf
+}
+
+// readOutput returns the contents of the file written to the output directory.
+func readOutput(t *testing.T, conf *alpha.DarknessConfig, filename yunyun.RelativePathFile) string {
+	t.Helper()
+	data, err := os.ReadFile(string(conf.Project.SourceToOutput(conf.Runtime.WorkDir.Join(filename))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
@@ -0,0 +1,120 @@
+package misa
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/yunyun"
+	"github.com/thecsw/rei"
+)
+
+// Neighbours are the previous and next pages of pages, keyed by their locations.
+type Neighbours map[yunyun.RelativePathDir][2]*yunyun.Neighbour
+
+// Dependent returns true if the page lists, links, or is linked from other pages, so it
+// has to wait for every page to be parsed.
+func Dependent(conf *alpha.DarknessConfig, page *yunyun.Page) bool {
+	// Any page can be linked to from anywhere.
+	if conf.Backlinks.Enable || page.Accoutrement.AutoIndex.IsEnabled() || page.Series != nil {
+		return true
+	}
+	_, dated := getDate(page)
+	return conf.Siblings.Enable && dated && page.Location != "."
+}
+
+// Dynamic returns true if the page can't be cached at all, because it lists
+// other pages or is in a series, which partial builds don't see in whole.
+// Other dependent pages are cached along with their context.
+func Dynamic(page *yunyun.Page) bool {
+	return page.Accoutrement.AutoIndex.IsEnabled() || page.Series != nil
+}
+
+// Context returns the hash of what the page takes from other pages.
+func Context(page *yunyun.Page) string {
+	context := &strings.Builder{}
+	for _, neighbour := range []*yunyun.Neighbour{page.Previous, page.Next} {
+		if neighbour != nil {
+			fmt.Fprintf(context, "%s\x00%s\x00", neighbour.Location, neighbour.Title)
+		}
+		context.WriteString("\x01")
+	}
+	for _, backlink := range page.Backlinks {
+		fmt.Fprintf(context, "%s\x00%s\x00", backlink.Location, backlink.Title)
+	}
+	return rei.Sha256([]byte(context.String()))
+}
+
+// BuildNeighbours finds the neighbours of every page. Pages in a series
+// are linked within it, ordered by their explicit order and then dates.
+// Other dated pages are linked to their siblings, oldest first, if the
+// siblings are enabled. Drafts are never linked.
+func BuildNeighbours(conf *alpha.DarknessConfig, pages []*yunyun.Page) Neighbours {
+	series := make(map[string]Pages)
+	siblings := make(map[yunyun.RelativePathDir]Pages)
+	for _, page := range pages {
+		if page.Accoutrement.Draft.IsEnabled() {
+			continue
+		}
+		if page.Series != nil {
+			slug := yunyun.TagSlug(page.Series.Name)
+			series[slug] = append(series[slug], page)
+			continue
+		}
+		if _, found := getDate(page); found && conf.Siblings.Enable && page.Location != "." {
+			parent := yunyun.RelativePathDir(filepath.Dir(string(page.Location)))
+			siblings[parent] = append(siblings[parent], page)
+		}
+	}
+
+	neighbours := make(Neighbours)
+	for _, parts := range series {
+		sortOldestFirst(parts)
+		// Explicit orders go first.
+		sort.SliceStable(parts, func(i, j int) bool {
+			left, right := parts[i].Series.Order, parts[j].Series.Order
+			return left > 0 && (right < 1 || left < right)
+		})
+		neighbours.link(parts)
+	}
+	for _, pages := range siblings {
+		sortOldestFirst(pages)
+		neighbours.link(pages)
+	}
+	return neighbours
+}
+
+// link links the consecutive pages to each other.
+func (n Neighbours) link(pages Pages) {
+	for i, page := range pages {
+		found := [2]*yunyun.Neighbour{}
+		if i > 0 {
+			found[0] = &yunyun.Neighbour{Title: pages[i-1].Title, Location: pages[i-1].Location}
+		}
+		if i < len(pages)-1 {
+			found[1] = &yunyun.Neighbour{Title: pages[i+1].Title, Location: pages[i+1].Location}
+		}
+		n[page.Location] = found
+	}
+}
+
+// Apply sets the page's neighbours, if it has any.
+func (n Neighbours) Apply(page *yunyun.Page) {
+	found := n[page.Location]
+	page.Previous, page.Next = found[0], found[1]
+}
+
+// sortOldestFirst sorts the pages by their dates, oldest first, pages
+// without dates go last in the order of their locations.
+func sortOldestFirst(pages Pages) {
+	sortNewestFirst(pages)
+	dated := sort.Search(len(pages), func(i int) bool {
+		_, found := getDate(pages[i])
+		return !found
+	})
+	for i, j := 0, dated-1; i < j; i, j = i+1, j-1 {
+		pages[i], pages[j] = pages[j], pages[i]
+	}
+}
@@ -0,0 +1,69 @@
+package misa
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/yunyun"
+	"github.com/thecsw/gana"
+)
+
+const (
+	// paginationDirectory is where the following pages of a listing go,
+	// so the second page of `blog` is `blog/page/2`.
+	paginationDirectory = "page"
+)
+
+// paginate returns the listing pages of the items, which is a copy of the
+// page with the items appended, and if there are more items than fit on a
+// page, the rest go to the generated pages that follow it. All the pages
+// link to their neighbours. The passed page is left as it is.
+func paginate(conf *alpha.DarknessConfig, page *yunyun.Page, items []yunyun.ListItem) []*yunyun.Page {
+	first := page.Clone()
+	size := conf.Pagination.Size
+	if size < 1 || len(items) <= size {
+		first.Contents = append(first.Contents, &yunyun.Content{Type: yunyun.TypeList, List: items})
+		return []*yunyun.Page{first}
+	}
+
+	total := (len(items) + size - 1) / size
+	listings := make([]*yunyun.Page, 0, total)
+	for number := 1; number <= total; number++ {
+		list := &yunyun.Content{Type: yunyun.TypeList, List: items[(number-1)*size : gana.Min(number*size, len(items))]}
+		if number == 1 {
+			first.Contents = append(first.Contents, list)
+			listings = append(listings, first)
+			continue
+		}
+		listings = append(listings, generatedPage(conf, paginatedLocation(page.Location, number),
+			fmt.Sprintf("%s, page %d", page.Title, number), list))
+	}
+
+	for i, listing := range listings {
+		navigation := make([]string, 0, 3)
+		if i > 0 {
+			previous := string(conf.Runtime.Join(yunyun.RelativePathFile(listings[i-1].Location)))
+			navigation = append(navigation, fmt.Sprintf("[[%s][← Previous]]", previous))
+			listing.Links = append(listing.Links, yunyun.Link{Rel: "prev", Href: previous})
+		}
+		navigation = append(navigation, fmt.Sprintf("Page %d of %d", i+1, total))
+		if i < total-1 {
+			next := string(conf.Runtime.Join(yunyun.RelativePathFile(listings[i+1].Location)))
+			navigation = append(navigation, fmt.Sprintf("[[%s][Next →]]", next))
+			listing.Links = append(listing.Links, yunyun.Link{Rel: "next", Href: next})
+		}
+		listing.Contents = append(listing.Contents, &yunyun.Content{
+			Type:      yunyun.TypeParagraph,
+			Paragraph: strings.Join(navigation, " · "),
+			Options:   yunyun.InPaginationFlag,
+		})
+	}
+	return listings
+}
+
+// paginatedLocation returns the location of the listing's page number.
+func paginatedLocation(location yunyun.RelativePathDir, number int) yunyun.RelativePathDir {
+	return yunyun.RelativePathDir(filepath.Join(string(location), paginationDirectory, fmt.Sprintf("%d", number)))
+}
@@ -0,0 +1,68 @@
+package misa
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/thecsw/darkness/yunyun"
+)
+
+func TestPaginate(t *testing.T) {
+	conf := testConfig(t)
+	conf.Pagination.Size = 2
+
+	page := generatedPage(conf, "blog", "Blog")
+	items := make([]yunyun.ListItem, 5)
+	for i := range items {
+		items[i] = yunyun.ListItem{Level: 1, Text: fmt.Sprintf("Item %d", i+1)}
+	}
+	listings := paginate(conf, page, items)
+
+	if len(page.Contents) != 0 || len(page.Links) != 0 {
+		t.Errorf("passed page was changed, contents %+v, links %+v", page.Contents, page.Links)
+	}
+	if len(listings) != 3 {
+		t.Fatalf("got %d listing pages, want 3", len(listings))
+	}
+	tests := []struct {
+		location yunyun.RelativePathDir
+		items    int
+		links    []yunyun.Link
+	}{
+		{"blog", 2, []yunyun.Link{{Rel: "next", Href: "https://example.com/blog/page/2"}}},
+		{"blog/page/2", 2, []yunyun.Link{
+			{Rel: "prev", Href: "https://example.com/blog"},
+			{Rel: "next", Href: "https://example.com/blog/page/3"},
+		}},
+		{"blog/page/3", 1, []yunyun.Link{{Rel: "prev", Href: "https://example.com/blog/page/2"}}},
+	}
+	for i, test := range tests {
+		listing := listings[i]
+		if listing.Location != test.location {
+			t.Errorf("page %d location = %q, want %q", i+1, listing.Location, test.location)
+		}
+		if !reflect.DeepEqual(listing.Links, test.links) {
+			t.Errorf("page %d links = %+v, want %+v", i+1, listing.Links, test.links)
+		}
+		// Every page has its list and the navigation after it.
+		if len(listing.Contents) != 2 || len(listing.Contents[0].List) != test.items || !listing.Contents[1].IsPagination() {
+			t.Errorf("page %d contents = %+v, want %d items and the navigation", i+1, listing.Contents, test.items)
+		}
+	}
+}
+
+func TestPaginateSinglePage(t *testing.T) {
+	conf := testConfig(t)
+	page := generatedPage(conf, "blog", "Blog")
+	listings := paginate(conf, page, []yunyun.ListItem{{Level: 1, Text: "Item"}})
+	if len(listings) != 1 || listings[0] == page {
+		t.Fatalf("listings = %v, want a single copy of the page", listings)
+	}
+	if len(listings[0].Contents) != 1 || len(listings[0].Links) != 0 {
+		t.Errorf("listing = %+v, want only the list without navigation", listings[0])
+	}
+	if len(page.Contents) != 0 {
+		t.Errorf("passed page was changed, contents %+v", page.Contents)
+	}
+}
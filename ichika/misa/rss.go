@@ -2,16 +2,14 @@ package misa
 
 import (
 	"encoding/xml"
-	"os"
+	"io"
 	"path/filepath"
-	"sort"
 	"strings"
 	"time"
 
 	"github.com/thecsw/darkness/emilia/alpha"
 	"github.com/thecsw/darkness/emilia/narumi"
 	"github.com/thecsw/darkness/emilia/puck"
-	"github.com/thecsw/darkness/ichika/hizuru"
 	"github.com/thecsw/darkness/yunyun"
 	"github.com/thecsw/darkness/yunyun/rss"
 	"github.com/thecsw/gana"
@@ -23,47 +21,38 @@ const (
 )
 
 // GenerateRssFeed generates an RSS feed based on the given config and directories.
-func GenerateRssFeed(conf *alpha.DarknessConfig, rssFilename string, rssDirectories []string, dryRun bool) {
-	// Get all all the pages we can build out.
-	allPages := hizuru.BuildPagesSimple(conf, rssDirectories)
-	// Try to retrieve the top root page to get channel description. If not found, use the
-	// website's title as the description.
-	topPage := gana.First(gana.Filter(func(page *yunyun.Page) bool { return page.Location == "." }, allPages))
-	rootDescription := conf.RSS.Description
-	if topPage != nil {
-		rootDescription = getDescription(topPage, conf.Website.DescriptionLength*4)
-	}
-	// If both the top page and RSS config have no description, default to the title.
-	if len(rootDescription) < 1 {
-		rootDescription = conf.Title
-	}
-
-	sort.Slice(allPages, func(i, j int) bool { return allPages[i].Title < allPages[j].Title })
-
-	// Get all pages that have dates defined, we only use those to be included in the rss feed.
-	pages := Pages(gana.Filter(func(page *yunyun.Page) bool {
-		_, dateFound := getDate(page)
-		return dateFound
-	}, allPages))
+func GenerateRssFeed(conf *alpha.DarknessConfig, rssFilename string, rssDirectories []string, dryRun bool) error {
+	return writeRssFeed(conf, collectFeed(conf, rssFilename, rssDirectories), dryRun)
+}
 
-	// Sort the pages in descending order of dates.
-	sort.Sort(pages)
+// writeRssFeed writes the feed as RSS.
+func writeRssFeed(conf *alpha.DarknessConfig, f *feed, dryRun bool) error {
+	pages := f.dated
 
 	// Create RSS items.
 	items := make([]rss.Item, 0, len(pages))
+	podcast := false
 
 	func() {
 		defer puck.Stopwatch("Built RSS pages", "num", len(pages)).Record()
 		for _, page := range pages {
-			// Skip drafts.
-			if page.Accoutrement.Draft.IsEnabled() {
-				continue
+			// Create the category name and location, tags are categories too.
+			categoryName, categoryLocation := f.category(page)
+			categories := []*rss.Category{{Value: categoryName, Domain: conf.Url + string(categoryLocation)}}
+			for _, tag := range page.Tags {
+				categories = append(categories, &rss.Category{Value: tag, Domain: tagLink(conf, tag)})
 			}
-			// Create the category name and location.
-			categoryName, categoryLocation := page.Title, page.Location
-			if categoryPage := getCategory(page, allPages); categoryPage != nil {
-				categoryName = categoryPage.Title
-				categoryLocation = categoryPage.Location
+
+			// Embed the whole page if asked to.
+			var contentEncoded *rss.ContentEncoded
+			if conf.RSS.FullContent {
+				contentEncoded = &rss.ContentEncoded{Value: fullContent(conf, page)}
+			}
+
+			// Attach the media file, which makes the feed a podcast.
+			enclosure, itunes := resolveEnclosure(conf, page).rss()
+			if itunes != nil {
+				podcast = true
 			}
 
 			// Create the RSS item.
@@ -73,12 +62,14 @@ func GenerateRssFeed(conf *alpha.DarknessConfig, rssFilename string, rssDirector
 				Link:    conf.Url + string(page.Location),
 				Description: yunyun.FancyText(getDescription(page, conf.Website.DescriptionLength*4)) +
 					" [ Continue reading... ]",
-				Author:    page.Author,
-				Category:  &rss.Category{Value: categoryName, Domain: conf.Url + string(categoryLocation)},
-				Enclosure: &rss.Enclosure{},
-				Guid:      &rss.Guid{Value: conf.Url + string(page.Location), IsPermaLink: true},
-				PubDate:   mustDate(page).Format(rss.RSSFormat),
-				Source:    &rss.Source{Value: conf.Title, Url: conf.Url},
+				Author:         page.Author,
+				ContentEncoded: contentEncoded,
+				Categories:     categories,
+				Enclosure:      enclosure,
+				Itunes:         itunes,
+				Guid:           &rss.Guid{Value: conf.Url + string(page.Location), IsPermaLink: true},
+				PubDate:        mustDate(page).Format(rss.RSSFormat),
+				Source:         &rss.Source{Value: conf.Title, Url: conf.Url},
 			})
 		}
 	}()
@@ -97,9 +88,9 @@ func GenerateRssFeed(conf *alpha.DarknessConfig, rssFilename string, rssDirector
 		Version: rss.RSSVersion,
 		Channel: &rss.Channel{
 			XMLName:        xml.Name{},
-			Title:          yunyun.FancyText(conf.Title),
-			Link:           conf.Url,
-			Description:    yunyun.FancyText(rootDescription),
+			Title:          yunyun.FancyText(f.title),
+			Link:           f.link,
+			Description:    yunyun.FancyText(f.description),
 			Language:       conf.RSS.Language,
 			Copyright:      conf.RSS.Copyright,
 			ManagingEditor: conf.RSS.ManagingEditor,
@@ -114,51 +105,30 @@ func GenerateRssFeed(conf *alpha.DarknessConfig, rssFilename string, rssDirector
 		},
 	}
 
-	xmlTarget := "stdout"
-	xmlFile := os.Stdout
-	var err error
-	if !dryRun {
-		xmlTarget = string(conf.Runtime.WorkDir.Join(yunyun.RelativePathFile(rssFilename)))
-		xmlFile, err = os.Create(filepath.Clean(xmlTarget))
-		if err != nil {
-			puck.Logger.Error("Creating file", "path", xmlTarget, "err", err)
-			os.Exit(1)
-		}
+	if conf.RSS.FullContent {
+		feed.ContentNamespace = rss.ContentNamespace
 	}
-
-	encoder := xml.NewEncoder(xmlFile)
-	encoder.Indent("", "  ")
-	if err := encoder.Encode(feed); err != nil {
-		puck.Logger.Error("Encoding to xml", "path", xmlTarget, "err", err)
-		os.Exit(1)
+	if podcast {
+		feed.ItunesNamespace = rss.ItunesNamespace
 	}
-	if err := xmlFile.Close(); err != nil {
-		puck.Logger.Error("Closing file", "path", xmlTarget, "err", err)
-		os.Exit(1)
-	}
-	puck.Logger.Print("Created rss file", "path", xmlTarget)
+
+	return writeOutput(conf, f.filename, dryRun, func(w io.Writer) error {
+		encoder := xml.NewEncoder(w)
+		encoder.Indent("", "  ")
+		return encoder.Encode(feed)
+	})
 }
 
 // getDate takes a page and returns its date if any found.
 func getDate(page *yunyun.Page) (time.Time, bool) {
-	parsed := narumi.ConvertHoloscene(page.Date)
-	return parsed, parsed.Unix() != 0 && parsed.Day() != 31 && parsed.Year() != 2000
+	return narumi.PageDate(page)
 }
 
-var categoryCache = make(map[string]*yunyun.Page)
-
-func getCategory(page *yunyun.Page, pages Pages) *yunyun.Page {
+// getCategory returns the parent page of the page, which is its category,
+// out of the pages keyed by their locations. Nil if there is none.
+func getCategory(page *yunyun.Page, locations map[yunyun.RelativePathDir]*yunyun.Page) *yunyun.Page {
 	categoryName := strings.TrimSuffix(string(page.Location), "/"+filepath.Base(string(page.Location)))
-	if v, ok := categoryCache[categoryName]; ok {
-		return v
-	}
-	for _, allPage := range pages {
-		if allPage.Location == yunyun.RelativePathDir(categoryName) {
-			categoryCache[categoryName] = allPage
-			return allPage
-		}
-	}
-	return nil
+	return locations[yunyun.RelativePathDir(categoryName)]
 }
 
 // Pages is custom type of slice of pages to enable sorting.
@@ -181,6 +151,17 @@ func mustDate(v *yunyun.Page) time.Time {
 	return t
 }
 
+// getUpdated returns when the page was last updated and true if it's known,
+// which is its declared update date, but never earlier than the page's date.
+func getUpdated(page *yunyun.Page) (time.Time, bool) {
+	date, dateFound := getDate(page)
+	updated, found := narumi.PageUpdated(page)
+	if !found || (dateFound && updated.Before(date)) {
+		return date, dateFound
+	}
+	return updated, true
+}
+
 const (
 	// Minimum length of the description
 	descriptionMinLength = 14
@@ -194,8 +175,8 @@ func getDescription(page *yunyun.Page, length int) string {
 	// Find the first paragraph for description
 	description := ""
 	for _, content := range page.Contents {
-		// We are only looking for paragraphs
-		if !content.IsParagraph() {
+		// We are only looking for paragraphs, the pagination is not one
+		if !content.IsParagraph() || content.IsPagination() {
 			continue
 		}
 		// Skip holoscene times
@@ -204,7 +185,8 @@ func getDescription(page *yunyun.Page, length int) string {
 			continue
 		}
 
-		cleanText := yunyun.RemoveFormatting(paragraph[:gana.Min(len(paragraph), length+10)])
+		paragraph = plainText(paragraph)
+		cleanText := paragraph[:gana.Min(len(paragraph), length+10)]
 		description = cleanText[:gana.Max(len(cleanText)-10, 0)] + "..."
 		if len(description) < descriptionMinLength {
 			continue
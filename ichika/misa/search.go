@@ -0,0 +1,108 @@
+package misa
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/export/html"
+	"github.com/thecsw/darkness/yunyun"
+	"github.com/thecsw/darkness/yunyun/search"
+)
+
+const (
+	// searchWeightTitle is how much a term in the title weighs.
+	searchWeightTitle = 10
+	// searchWeightHeading is how much a term in a heading weighs.
+	searchWeightHeading = 3
+	// searchWeightText is how much a term in the body weighs.
+	searchWeightText = 1
+
+	// searchWidgetIndexPlaceholder is replaced with the index location
+	// when the widget is written.
+	searchWidgetIndexPlaceholder = "{{index}}"
+)
+
+// searchWidget is the vanilla js search box that reads the index.
+//
+//go:embed search.js
+var searchWidget string
+
+// WriteSearch writes the search index of all the passed pages and the
+// widget that searches through it.
+func WriteSearch(conf *alpha.DarknessConfig, pages []*yunyun.Page, dryRun bool) error {
+	index := buildSearchIndex(conf, pages)
+	if err := writeOutput(conf, string(conf.Search.Filename), dryRun, func(w io.Writer) error {
+		return json.NewEncoder(w).Encode(index)
+	}); err != nil {
+		return err
+	}
+
+	// The widget finds the index relative to itself, so it works from any page.
+	location, err := filepath.Rel(filepath.Dir(string(conf.Search.Widget)), string(conf.Search.Filename))
+	if err != nil {
+		return fmt.Errorf("locating search index %s from %s: %w", conf.Search.Filename, conf.Search.Widget, err)
+	}
+	widget := strings.ReplaceAll(searchWidget, searchWidgetIndexPlaceholder, filepath.ToSlash(location))
+	return writeOutput(conf, string(conf.Search.Widget), dryRun, func(w io.Writer) error {
+		_, err := io.WriteString(w, widget)
+		return err
+	})
+}
+
+// buildSearchIndex indexes the titles, headings, paragraphs, and lists
+// of the pages, drafts are left out.
+func buildSearchIndex(conf *alpha.DarknessConfig, pages []*yunyun.Page) *search.Index {
+	pages = sortPages(pages)
+	index := &search.Index{
+		Version:   search.SearchVersion,
+		Documents: make([]search.Document, 0, len(pages)),
+		Terms:     make(map[string][]int),
+	}
+	for _, page := range pages {
+		if page.Accoutrement.Draft.IsEnabled() {
+			continue
+		}
+		document := search.Document{
+			Title:       yunyun.RemoveFormatting(page.Title),
+			Url:         string(conf.Runtime.Join(yunyun.RelativePathFile(page.Location))),
+			Description: getDescription(page, conf.Website.DescriptionLength),
+			Headings:    make([]search.Heading, 0, 8),
+		}
+		weights := make(map[string]int)
+		add := func(text string, weight int) {
+			for _, term := range search.Terms(text) {
+				weights[term] += weight
+			}
+		}
+		add(document.Title, searchWeightTitle)
+		for _, content := range page.Contents {
+			switch {
+			case content.IsHeading():
+				heading := search.Heading{
+					Text: yunyun.RemoveFormatting(content.Heading),
+					Id:   html.ExtractID(content.Heading),
+				}
+				document.Headings = append(document.Headings, heading)
+				add(heading.Text, searchWeightHeading)
+			case content.IsParagraph():
+				add(plainText(content.Paragraph), searchWeightText)
+			case content.IsList() || content.IsListNumbered():
+				for _, item := range content.List {
+					add(plainText(item.Text), searchWeightText)
+				}
+			}
+		}
+
+		position := len(index.Documents)
+		index.Documents = append(index.Documents, document)
+		for term, weight := range weights {
+			index.Terms[term] = append(index.Terms[term], position, weight)
+		}
+	}
+	return index
+}
@@ -0,0 +1,202 @@
+// Darkness search widget, drop it into any page with
+//
+//   <div data-darkness-search></div>
+//   <script src="search.js" defer></script>
+//
+// If the page has no search container, the box is put right before
+// the script (or at the top of the body). The index is fetched the
+// first time somebody searches.
+(function () {
+  "use strict";
+
+  const script = document.currentScript;
+  const indexUrl = new URL(script.dataset.index || "{{index}}", script.src);
+  const maxResults = parseInt(script.dataset.results || "10", 10);
+
+  // These must match the stemming rules of yunyun/search.
+  const minStemLength = 3;
+  const suffixes = [
+    ["ational", "ate"],
+    ["ization", "ize"],
+    ["fulness", "ful"],
+    ["ousness", "ous"],
+    ["iveness", "ive"],
+    ["ingly", ""],
+    ["edly", ""],
+    ["ments", ""],
+    ["ment", ""],
+    ["ness", ""],
+    ["ies", "y"],
+    ["ing", ""],
+    ["ly", ""],
+    ["ed", ""],
+    ["ss", "ss"],
+    ["s", ""],
+  ];
+  const stopWords = new Set([
+    "a", "an", "and", "are", "as", "at", "be", "but", "by", "for", "from",
+    "if", "in", "into", "is", "it", "its", "of", "on", "or", "so", "that",
+    "the", "their", "then", "there", "these", "they", "this", "to", "was",
+    "were", "will", "with",
+  ]);
+
+  function stem(word) {
+    for (const [suffix, replacement] of suffixes) {
+      if (!word.endsWith(suffix)) {
+        continue;
+      }
+      const base = word.slice(0, word.length - suffix.length);
+      if (Array.from(base).length < minStemLength) {
+        return word;
+      }
+      return base + replacement;
+    }
+    return word;
+  }
+
+  function terms(text) {
+    return (text.toLowerCase().match(/[\p{L}\p{N}]+/gu) || [])
+      .filter((word) => !stopWords.has(word) && Array.from(word).length > 1)
+      .map(stem);
+  }
+
+  let index = null;
+  function loadIndex() {
+    if (index === null) {
+      index = fetch(indexUrl).then((response) => {
+        if (!response.ok) {
+          throw new Error("darkness search: " + response.status + " " + indexUrl);
+        }
+        return response.json();
+      });
+    }
+    return index;
+  }
+
+  // search returns the documents that have every term, the last term
+  // also matches as a prefix, so results show up while typing.
+  function search(data, query) {
+    const wanted = terms(query);
+    if (wanted.length === 0) {
+      return [];
+    }
+    let scores = null;
+    wanted.forEach((term, i) => {
+      const found = new Map();
+      const matching = i === wanted.length - 1
+        ? Object.keys(data.terms).filter((key) => key.startsWith(term))
+        : [term];
+      for (const key of matching) {
+        const postings = data.terms[key] || [];
+        for (let j = 0; j < postings.length; j += 2) {
+          found.set(postings[j], (found.get(postings[j]) || 0) + postings[j + 1]);
+        }
+      }
+      if (scores === null) {
+        scores = found;
+        return;
+      }
+      for (const [document, score] of scores) {
+        if (found.has(document)) {
+          scores.set(document, score + found.get(document));
+        } else {
+          scores.delete(document);
+        }
+      }
+    });
+    return Array.from(scores)
+      .sort((a, b) => b[1] - a[1])
+      .slice(0, maxResults)
+      .map(([document]) => {
+        const result = Object.assign({}, data.documents[document]);
+        // Link to the first heading that mentions any of the terms.
+        const heading = (result.headings || []).find((heading) =>
+          terms(heading.text).some((term) => wanted.some((want) => term.startsWith(want))));
+        if (heading) {
+          result.heading = heading;
+        }
+        return result;
+      });
+  }
+
+  function render(list, results, query) {
+    list.replaceChildren();
+    if (query.trim() !== "" && results.length === 0) {
+      const empty = document.createElement("li");
+      empty.className = "darkness-search-empty";
+      empty.textContent = "Nothing found";
+      list.appendChild(empty);
+      return;
+    }
+    for (const result of results) {
+      const item = document.createElement("li");
+      const link = document.createElement("a");
+      link.href = result.url;
+      link.textContent = result.title;
+      item.appendChild(link);
+      if (result.heading) {
+        const section = document.createElement("a");
+        section.className = "darkness-search-heading";
+        section.href = result.url + "#" + result.heading.id;
+        section.textContent = result.heading.text;
+        item.appendChild(document.createTextNode(" › "));
+        item.appendChild(section);
+      }
+      if (result.description) {
+        const description = document.createElement("p");
+        description.textContent = result.description;
+        item.appendChild(description);
+      }
+      list.appendChild(item);
+    }
+  }
+
+  function mount(container) {
+    container.classList.add("darkness-search");
+    const input = document.createElement("input");
+    input.type = "search";
+    input.className = "darkness-search-input";
+    input.placeholder = container.dataset.placeholder || "Search";
+    input.setAttribute("aria-label", input.placeholder);
+    const list = document.createElement("ul");
+    list.className = "darkness-search-results";
+    container.append(input, list);
+
+    let timer = null;
+    input.addEventListener("input", () => {
+      clearTimeout(timer);
+      timer = setTimeout(() => {
+        const query = input.value;
+        loadIndex()
+          .then((data) => {
+            // Don't render stale results over newer ones.
+            if (query === input.value) {
+              render(list, search(data, query), query);
+            }
+          })
+          .catch((err) => console.error(err));
+      }, 100);
+    });
+  }
+
+  function init() {
+    let containers = document.querySelectorAll("[data-darkness-search]");
+    if (containers.length === 0) {
+      const container = document.createElement("div");
+      // Scripts in the head can't hold the box, so it goes into the body.
+      if (document.body.contains(script)) {
+        script.parentNode.insertBefore(container, script);
+      } else {
+        document.body.prepend(container);
+      }
+      containers = [container];
+    }
+    containers.forEach(mount);
+  }
+
+  if (document.readyState === "loading") {
+    document.addEventListener("DOMContentLoaded", init);
+  } else {
+    init();
+  }
+})();
@@ -0,0 +1,105 @@
+package misa
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/thecsw/darkness/yunyun"
+	"github.com/thecsw/darkness/yunyun/search"
+)
+
+func TestBuildSearchIndex(t *testing.T) {
+	conf := testConfig(t)
+
+	page := func(location yunyun.RelativePathDir, title string, contents ...*yunyun.Content) *yunyun.Page {
+		page := yunyun.NewPage(
+			yunyun.WithFilename(yunyun.RelativePathFile(filepath.Join(string(location), "index.org"))),
+			yunyun.WithLocation(location),
+			yunyun.WithContents(contents),
+		)
+		page.Title = title
+		return page
+	}
+	draft := page("drafts", "Secret whales")
+	draft.Accoutrement.Draft.Enable()
+	pages := []*yunyun.Page{
+		// Pages are indexed by their locations, whatever order they come in.
+		page("whales", "Whales",
+			&yunyun.Content{Type: yunyun.TypeHeading, HeadingLevel: 2, Heading: "Sailing whales"},
+			&yunyun.Content{Type: yunyun.TypeParagraph, Paragraph: "The *whale* is sailing."},
+		),
+		draft,
+		page("ships", "Ships",
+			&yunyun.Content{Type: yunyun.TypeList, List: []yunyun.ListItem{{Level: 1, Text: "Whales near ships"}}},
+		),
+	}
+
+	index := buildSearchIndex(conf, pages)
+	if index.Version != search.SearchVersion {
+		t.Errorf("version = %d, want %d", index.Version, search.SearchVersion)
+	}
+	titles := make([]string, 0, len(index.Documents))
+	for _, document := range index.Documents {
+		titles = append(titles, document.Title)
+	}
+	if want := []string{"Ships", "Whales"}; !reflect.DeepEqual(titles, want) {
+		t.Fatalf("documents = %q, want %q without the draft", titles, want)
+	}
+	if got, want := index.Documents[1].Url, "https://example.com/whales"; got != want {
+		t.Errorf("url = %q, want %q", got, want)
+	}
+	if got := index.Documents[1].Headings; len(got) != 1 || got[0].Text != "Sailing whales" || got[0].Id == "" {
+		t.Errorf("headings = %+v, want the single heading with an id", got)
+	}
+
+	// Terms are flat pairs of document positions and weights.
+	tests := []struct {
+		term string
+		want []int
+	}{
+		// Ships has it once in a list, Whales in the title, a heading, and a paragraph.
+		{"whale", []int{0, searchWeightText, 1, searchWeightTitle + searchWeightHeading + searchWeightText}},
+		{"ship", []int{0, searchWeightTitle + searchWeightText}},
+		{"sail", []int{1, searchWeightHeading + searchWeightText}},
+		{"secret", nil},
+		{"the", nil},
+	}
+	for _, test := range tests {
+		if got := index.Terms[test.term]; !reflect.DeepEqual(got, test.want) {
+			t.Errorf("terms[%q] = %v, want %v", test.term, got, test.want)
+		}
+	}
+}
+
+func TestBuildSearchIndexLiteralGuards(t *testing.T) {
+	conf := testConfig(t)
+	// Markdown parsers guard the marking characters of literal text.
+	const guarded = "Find the \u2060*asterisk*\u2060 in \u2060/tmp/\u2060"
+	page := yunyun.NewPage(
+		yunyun.WithFilename("index.org"),
+		yunyun.WithLocation("."),
+		yunyun.WithContents([]*yunyun.Content{
+			{Type: yunyun.TypeHeading, HeadingLevel: 2, Heading: guarded},
+			{Type: yunyun.TypeParagraph, Paragraph: guarded},
+		}),
+	)
+	page.Title = guarded
+
+	index := buildSearchIndex(conf, []*yunyun.Page{page})
+	for term := range index.Terms {
+		if strings.Contains(term, "\u2060") {
+			t.Errorf("term %q has a literal guard", term)
+		}
+	}
+	if _, ok := index.Terms["asterisk"]; !ok {
+		t.Errorf("terms = %v, want them to have %q", index.Terms, "asterisk")
+	}
+	document := index.Documents[0]
+	for _, text := range []string{document.Title, document.Description, document.Headings[0].Text} {
+		if strings.Contains(text, "\u2060") {
+			t.Errorf("document text %q has a literal guard", text)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package misa
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/emilia/puck"
+	"github.com/thecsw/darkness/yunyun"
+	"github.com/thecsw/darkness/yunyun/sitemap"
+	"github.com/thecsw/gana"
+)
+
+// sitemapChangefreqs are the allowed values of change frequencies.
+var sitemapChangefreqs = []string{"always", "hourly", "daily", "weekly", "monthly", "yearly", "never"}
+
+// WriteSitemap writes the sitemap of all the passed pages, except for
+// drafts and pages that opted out with `sitemap:nil`.
+func WriteSitemap(conf *alpha.DarknessConfig, pages []*yunyun.Page, dryRun bool) error {
+	urls := make([]sitemap.Url, 0, len(pages))
+	for _, page := range pages {
+		if page.Accoutrement.Draft.IsEnabled() || page.Accoutrement.Sitemap.IsDisabled() {
+			continue
+		}
+		url := sitemap.Url{
+			// Same as the page's canonical link.
+			Loc:        string(conf.Runtime.Join(yunyun.RelativePathFile(page.Location))),
+			Changefreq: page.Accoutrement.SitemapChangefreq,
+			Priority:   page.Accoutrement.SitemapPriority,
+		}
+		if updated, found := getUpdated(page); found {
+			url.Lastmod = updated.Format(sitemap.SitemapFormat)
+		}
+		if len(url.Changefreq) > 0 && !gana.Any(url.Changefreq, sitemapChangefreqs) {
+			puck.Logger.Warn("Unknown sitemap change frequency, ignoring", "page", page.File, "changefreq", url.Changefreq)
+			url.Changefreq = ""
+		}
+		if priority, err := strconv.ParseFloat(url.Priority, 64); len(url.Priority) > 0 && (err != nil || priority < 0 || priority > 1) {
+			puck.Logger.Warn("Sitemap priority should be from 0.0 to 1.0, ignoring", "page", page.File, "priority", url.Priority)
+			url.Priority = ""
+		}
+		urls = append(urls, url)
+	}
+	// The same pages make the same sitemap, whatever order they were built in.
+	sort.Slice(urls, func(i, j int) bool { return urls[i].Loc < urls[j].Loc })
+
+	return writeOutput(conf, string(conf.Sitemap.Filename), dryRun, func(w io.Writer) error {
+		if _, err := io.WriteString(w, xml.Header); err != nil {
+			return err
+		}
+		encoder := xml.NewEncoder(w)
+		encoder.Indent("", "  ")
+		return encoder.Encode(&sitemap.UrlSet{Namespace: sitemap.SitemapNamespace, Urls: urls})
+	})
+}
+
+// WriteRobots writes robots.txt, which points crawlers to the sitemap
+// and asks them to stay away from the disallowed paths.
+func WriteRobots(conf *alpha.DarknessConfig, dryRun bool) error {
+	// Robots live at the root of the host, so paths include the site's path.
+	root := "/"
+	if conf.Runtime.UrlPath != nil {
+		root = strings.TrimSuffix(conf.Runtime.UrlPath.Path, "/") + "/"
+	}
+	robots := &strings.Builder{}
+	robots.WriteString("User-agent: *\n")
+	if len(conf.Sitemap.Disallow) < 1 {
+		robots.WriteString("Disallow:\n")
+	}
+	for _, disallow := range conf.Sitemap.Disallow {
+		fmt.Fprintf(robots, "Disallow: %s%s\n", root, strings.TrimPrefix(disallow, "/"))
+	}
+	fmt.Fprintf(robots, "\nSitemap: %s\n", conf.Runtime.Join(conf.Sitemap.Filename))
+
+	return writeOutput(conf, string(puck.RobotsFilename), dryRun, func(w io.Writer) error {
+		_, err := io.WriteString(w, robots.String())
+		return err
+	})
+}
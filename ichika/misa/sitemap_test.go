@@ -0,0 +1,73 @@
+package misa
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+
+	"github.com/thecsw/darkness/yunyun"
+	"github.com/thecsw/darkness/yunyun/sitemap"
+)
+
+func TestWriteSitemap(t *testing.T) {
+	conf := testConfig(t)
+
+	page := func(location yunyun.RelativePathDir, date, updated string) *yunyun.Page {
+		page := yunyun.NewPage(yunyun.WithLocation(location))
+		page.Date, page.Updated = date, updated
+		return page
+	}
+	draft := page("drafts", "", "")
+	draft.Accoutrement.Draft.Enable()
+	hidden := page("hidden", "", "")
+	hidden.Accoutrement.Sitemap.Disable()
+	tuned := page("tuned", "", "")
+	tuned.Accoutrement.SitemapChangefreq, tuned.Accoutrement.SitemapPriority = "weekly", "0.8"
+	wrong := page("wrong", "", "")
+	wrong.Accoutrement.SitemapChangefreq, wrong.Accoutrement.SitemapPriority = "sometimes", "2"
+	pages := []*yunyun.Page{
+		page("updated", "10; 12023 H.E.", "20; 12024 H.E."),
+		page("dated", "10; 12023 H.E.", ""),
+		page("undated", "", "20; 12024 H.E."),
+		draft, hidden, tuned, wrong,
+	}
+	if err := WriteSitemap(conf, pages, false); err != nil {
+		t.Fatal(err)
+	}
+
+	set := &sitemap.UrlSet{}
+	if err := xml.Unmarshal([]byte(readOutput(t, conf, conf.Sitemap.Filename)), set); err != nil {
+		t.Fatal(err)
+	}
+	// Urls are sorted, drafts and pages that opted out are left out.
+	want := []sitemap.Url{
+		{Loc: "https://example.com/dated", Lastmod: "2023-01-10"},
+		{Loc: "https://example.com/tuned", Changefreq: "weekly", Priority: "0.8"},
+		{Loc: "https://example.com/undated", Lastmod: "2024-01-20"},
+		{Loc: "https://example.com/updated", Lastmod: "2024-01-20"},
+		{Loc: "https://example.com/wrong"},
+	}
+	if !reflect.DeepEqual(set.Urls, want) {
+		t.Errorf("urls = %+v, want %+v", set.Urls, want)
+	}
+}
+
+func TestWriteRobots(t *testing.T) {
+	conf := testConfig(t)
+	if err := WriteRobots(conf, false); err != nil {
+		t.Fatal(err)
+	}
+	want := "User-agent: *\nDisallow:\n\nSitemap: https://example.com/sitemap.xml\n"
+	if got := readOutput(t, conf, "robots.txt"); got != want {
+		t.Errorf("robots.txt = %q, want %q", got, want)
+	}
+
+	conf.Sitemap.Disallow = []string{"/drafts/", "private"}
+	if err := WriteRobots(conf, false); err != nil {
+		t.Fatal(err)
+	}
+	want = "User-agent: *\nDisallow: /drafts/\nDisallow: /private\n\nSitemap: https://example.com/sitemap.xml\n"
+	if got := readOutput(t, conf, "robots.txt"); got != want {
+		t.Errorf("robots.txt = %q, want %q", got, want)
+	}
+}
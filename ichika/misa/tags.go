@@ -0,0 +1,121 @@
+package misa
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/ichika/chiho"
+	"github.com/thecsw/darkness/yunyun"
+)
+
+// tag is a tag with all the pages that declared it.
+type tag struct {
+	// name is how the tag was first spelled.
+	name string
+	// slug is the url-friendly name of the tag.
+	slug string
+	// pages are the tagged pages, newest first.
+	pages Pages
+}
+
+// TagPages returns the generated listing page of every tag and the tag
+// index, which lists all the tags. Drafts are never listed.
+func TagPages(conf *alpha.DarknessConfig, pages []*yunyun.Page) []*yunyun.Page {
+	tags := collectTags(pages)
+	generated := make([]*yunyun.Page, 0, len(tags)+1)
+
+	index := make([]yunyun.ListItem, 0, len(tags))
+	for _, tag := range tags {
+		index = append(index, yunyun.ListItem{
+			Level: 1,
+			Text:  fmt.Sprintf("[[%s][%s]] (%d)", tagLink(conf, tag.name), tag.name, len(tag.pages)),
+		})
+		items := make([]yunyun.ListItem, 0, len(tag.pages))
+		for _, page := range tag.pages {
+			items = append(items, pageListItem(conf, page))
+		}
+		listing := generatedPage(conf, tagLocation(conf, tag.name), tag.name,
+			&yunyun.Content{Type: yunyun.TypeParagraph, Paragraph: fmt.Sprintf("Pages tagged with %s.", tag.name)},
+		)
+		generated = append(generated, paginate(conf, listing, items)...)
+	}
+	generated = append(generated, generatedPage(conf, conf.Tags.Directory, conf.Tags.Title,
+		&yunyun.Content{Type: yunyun.TypeList, List: index},
+	))
+	return generated
+}
+
+// collectTags groups the pages by their tags, sorted by the tag slugs.
+func collectTags(pages []*yunyun.Page) []*tag {
+	// Sorted pages spell every tag the same way between builds.
+	pages = sortPages(pages)
+
+	bySlug := make(map[string]*tag)
+	for _, page := range pages {
+		if page.Accoutrement.Draft.IsEnabled() {
+			continue
+		}
+		for _, name := range page.Tags {
+			slug := yunyun.TagSlug(name)
+			found, ok := bySlug[slug]
+			if !ok {
+				found = &tag{name: name, slug: slug}
+				bySlug[slug] = found
+			}
+			found.pages = append(found.pages, page)
+		}
+	}
+	tags := make([]*tag, 0, len(bySlug))
+	for _, tag := range bySlug {
+		sortNewestFirst(tag.pages)
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].slug < tags[j].slug })
+	return tags
+}
+
+// tagLocation returns the directory of the tag's listing page.
+func tagLocation(conf *alpha.DarknessConfig, name string) yunyun.RelativePathDir {
+	return yunyun.RelativePathDir(filepath.Join(string(conf.Tags.Directory), yunyun.TagSlug(name)))
+}
+
+// tagLink returns the link to the tag's listing page, empty if tag
+// pages aren't generated.
+func tagLink(conf *alpha.DarknessConfig, name string) string {
+	if !conf.Tags.Enable {
+		return ""
+	}
+	return string(conf.Runtime.Join(yunyun.RelativePathFile(tagLocation(conf, name))))
+}
+
+// generatedPage returns an enriched page that doesn't come from any
+// file, so it's built out of the passed contents.
+func generatedPage(conf *alpha.DarknessConfig, location yunyun.RelativePathDir, title string, contents ...*yunyun.Content) *yunyun.Page {
+	page := yunyun.NewPage(
+		yunyun.WithFilename(yunyun.JoinRelativePaths(location, "index")),
+		yunyun.WithLocation(location),
+		yunyun.WithContents(contents),
+	)
+	page.Title = title
+	page.Date = ""
+	page.DateHoloscene = false
+	return chiho.EnrichPage(conf, page)
+}
+
+// sortNewestFirst sorts the pages by their dates, newest first, pages
+// without dates go last in the order of their locations.
+func sortNewestFirst(pages Pages) {
+	sort.SliceStable(pages, func(i, j int) bool {
+		left, leftFound := getDate(pages[i])
+		right, rightFound := getDate(pages[j])
+		switch {
+		case leftFound && rightFound && !left.Equal(right):
+			return left.After(right)
+		case leftFound != rightFound:
+			return leftFound
+		}
+		return pages[i].Location < pages[j].Location
+	})
+}
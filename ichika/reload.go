@@ -0,0 +1,83 @@
+package ichika
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/thecsw/darkness/emilia/puck"
+)
+
+const (
+	// reloadEventPage tells the browsers to reload the page.
+	reloadEventPage = "reload"
+	// reloadEventStyles tells the browsers to only swap the stylesheets.
+	reloadEventStyles = "css"
+)
+
+// reloader is the server-sent events broker that tells all the open
+// tabs to reload after the watcher rebuilds the site.
+type reloader struct {
+	// clients are the channels of connected browsers.
+	clients map[chan string]struct{}
+	// mu guards the clients.
+	mu sync.Mutex
+}
+
+// newReloader returns a broker with no clients.
+func newReloader() *reloader {
+	return &reloader{clients: make(map[chan string]struct{})}
+}
+
+// broadcast sends the event to every connected browser, browsers that
+// are too busy to listen will simply miss it.
+func (r *reloader) broadcast(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for client := range r.clients {
+		select {
+		case client <- event:
+		default:
+		}
+	}
+}
+
+// ServeHTTP holds the connection open and streams the events.
+func (r *reloader) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	rc := http.NewResponseController(w)
+	// The connection lives for as long as the tab is open.
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		puck.Logger.Error("Clearing live reload deadline", "err", err)
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	client := make(chan string, 1)
+	r.mu.Lock()
+	r.clients[client] = struct{}{}
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		delete(r.clients, client)
+		r.mu.Unlock()
+	}()
+
+	fmt.Fprint(w, ": connected\n\n")
+	if err := rc.Flush(); err != nil {
+		puck.Logger.Error("Flushing live reload", "err", err)
+		return
+	}
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+		case event := <-client:
+			fmt.Fprintf(w, "event: %s\ndata: {}\n\n", event)
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -5,19 +5,16 @@ import (
 	"os"
 	"os/exec"
 	"os/signal"
-	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/charmbracelet/log"
-	"github.com/fsnotify/fsnotify"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/thecsw/darkness/emilia/alpha"
 	"github.com/thecsw/darkness/emilia/puck"
-	"github.com/thecsw/darkness/ichika/hizuru"
-	"github.com/thecsw/darkness/yunyun"
 )
 
 const (
@@ -36,12 +33,18 @@ func ServeCommandFunc() {
 	options.Url = "http://127.0.0.1:" + strconv.Itoa(*port)
 	// Override the output extension to .html
 	options.OutputExtension = puck.ExtensionHtml
+	// Served pages reload themselves on rebuilds.
+	options.LiveReload = true
 	// emilia.InitDarkness(options)
 	conf := alpha.BuildConfig(options)
+	site := &servedSite{conf: conf}
 
 	puck.Logger.SetPrefix("Server 🍩 ")
 
-	build(conf)
+	// A broken feed or sitemap shouldn't stop the pages from being served.
+	if err := build(conf); err != nil {
+		puck.Logger.Error("Building", "err", err)
+	}
 	puck.Logger.Print("Serving the files", "url", options.Url)
 
 	r := chi.NewRouter()
@@ -58,8 +61,12 @@ func ServeCommandFunc() {
 		WriteTimeout:      10 * time.Second,
 	}
 
-	// Tune it to serve local files.
-	fileServer(r, "/", http.Dir(string(conf.Runtime.WorkDir)))
+	// Open tabs listen here to know when to reload.
+	reload := newReloader()
+	r.Handle(puck.LiveReloadEndpoint, reload)
+
+	// Tune it to serve local files, wherever the current config puts them.
+	fileServer(r, "/", site)
 
 	// Spin the local server up.
 	go func() {
@@ -67,7 +74,7 @@ func ServeCommandFunc() {
 	}()
 
 	// File watcher will rebuild dir if any files change.
-	go launchWatcher(conf)
+	go launchWatcher(site, options, reload)
 	puck.Logger.Print("Launched file watcher")
 
 	// Try to open the local server with `open` command.
@@ -83,73 +90,37 @@ func ServeCommandFunc() {
 	<-sigint
 	puck.Logger.Print("Shutting down the server + cleaning up")
 	isQuietMegumin = true
-	removeOutputFiles(conf)
+	removeOutputFiles(site.Config())
 	puck.Logger.Print("farewell")
 }
 
-// launchWatcher watches for any file creations, changes, modifications, deletions
-// and rebuilds the directory as that happens.
-func launchWatcher(conf *alpha.DarknessConfig) {
-	// Create new watcher.
-	watcher, err := fsnotify.NewWatcher()
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer watcher.Close()
+// servedSite is the config of the served site, which the watcher swaps
+// when darkness.toml changes.
+type servedSite struct {
+	// conf is the current config.
+	conf *alpha.DarknessConfig
+	// mu guards the config.
+	mu sync.RWMutex
+}
 
-	// Start listening for events.
-	go func() {
-		for {
-			select {
-			case event, ok := <-watcher.Events:
-				if !ok {
-					puck.Logger.Warn("stopped watching")
-					return
-				}
-				filename := string(conf.Runtime.WorkDir.Rel(yunyun.FullPathFile(event.Name)))
-				if strings.HasSuffix(filename, conf.Project.Output) ||
-					strings.HasPrefix(filepath.Base(filename), `.`) {
-					continue
-				}
-				// Skip CHMOD events that IDE and editors do by default
-				if event.Has(fsnotify.Chmod) {
-					continue
-				}
-				if event.Has(fsnotify.Write) {
-					puck.Logger.Warn("A file was modified", "path", filename)
-				}
-				if event.Has(fsnotify.Create) {
-					puck.Logger.Warn("A file was created", "path", filename)
-				}
-				if event.Has(fsnotify.Remove) {
-					puck.Logger.Warn("A file was removed", "path", filename)
-				}
-				if event.Has(fsnotify.Rename) {
-					puck.Logger.Warn("A file was renamed", "path", filename)
-				}
-				build(conf)
-			case err, ok := <-watcher.Errors:
-				if !ok {
-					puck.Logger.Warn("Watcher is leaving")
-					return
-				}
-				puck.Logger.Error("Watcher", "err", err)
-			}
-		}
-	}()
+// Config returns the current config.
+func (s *servedSite) Config() *alpha.DarknessConfig {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.conf
+}
 
-	// start adding all the source files
-	for _, inputFilenameToWatch := range hizuru.FindFilesByExtSimple(conf) {
-		err = watcher.Add(string(inputFilenameToWatch))
-		if err != nil {
-			log.Fatal(err)
-		}
-	}
-	puck.Logger.Print("Listening to file changes", "num", len(watcher.WatchList()), "dir", workDir)
+// Swap replaces the current config with the new one.
+func (s *servedSite) Swap(conf *alpha.DarknessConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.conf = conf
+}
 
-	puck.Logger.Print("Press Ctrl-C to stop the server")
-	// Block main goroutine forever.
-	<-make(chan struct{})
+// Open opens the file from the output directory of the current config,
+// so the server follows the output directory when it changes.
+func (s *servedSite) Open(name string) (http.File, error) {
+	return http.Dir(string(s.Config().Project.OutputRoot())).Open(name)
 }
 
 // fileServer conveniently sets up a http.FileServer handler to serve
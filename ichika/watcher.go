@@ -0,0 +1,302 @@
+package ichika
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/fsnotify/fsnotify"
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/emilia/puck"
+	"github.com/thecsw/darkness/ichika/hizuru"
+	"github.com/thecsw/darkness/yunyun"
+)
+
+// watcherDebounce is how long the watcher waits for more events
+// before rebuilding, as editors save files in bursts.
+const watcherDebounce = 100 * time.Millisecond
+
+// siteWatcher is the state of the serve watcher.
+type siteWatcher struct {
+	// conf is the config that gets rebuilt when darkness.toml changes.
+	conf *alpha.DarknessConfig
+	// site is where the rebuilt config is handed over to the server.
+	site *servedSite
+	// options are the options the config was built with.
+	options alpha.Options
+	// reload tells the browsers to reload.
+	reload *reloader
+	// watcher is the underlying fsnotify watcher.
+	watcher *fsnotify.Watcher
+	// dirs are the directories being watched.
+	dirs map[yunyun.FullPathDir]struct{}
+}
+
+// launchWatcher watches for any file creations, changes, modifications, deletions
+// and rebuilds the directory as that happens, then tells the browsers to reload.
+func launchWatcher(site *servedSite, options alpha.Options, reload *reloader) {
+	// Create new watcher.
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer watcher.Close()
+
+	w := &siteWatcher{
+		conf:    site.Config(),
+		site:    site,
+		options: options,
+		reload:  reload,
+		watcher: watcher,
+		dirs:    make(map[yunyun.FullPathDir]struct{}),
+	}
+
+	// Watch all the project directories, so new files are seen too. This
+	// is done before listening, as the events change the watched ones.
+	w.watch(yunyun.FullPathDir(w.conf.Runtime.WorkDir))
+	// The config might live outside of the project.
+	if err := watcher.Add(filepath.Dir(w.conf.Runtime.ConfigFile)); err != nil {
+		puck.Logger.Warn("Couldn't watch the config", "path", w.conf.Runtime.ConfigFile, "err", err)
+	}
+	puck.Logger.Print("Listening to file changes", "num", len(w.dirs), "dir", workDir)
+
+	// Start listening for events, which are collected until the editor
+	// is done saving and then applied all at once.
+	go func() {
+		changes := make(map[yunyun.FullPathFile]fsnotify.Op)
+		debounce := time.NewTimer(watcherDebounce)
+		debounce.Stop()
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					puck.Logger.Warn("stopped watching")
+					return
+				}
+				if w.isIgnored(yunyun.FullPathFile(event.Name)) {
+					continue
+				}
+				// Skip CHMOD events that IDE and editors do by default
+				if event.Has(fsnotify.Chmod) {
+					continue
+				}
+				filename := string(w.conf.Runtime.WorkDir.Rel(yunyun.FullPathFile(event.Name)))
+				if event.Has(fsnotify.Write) {
+					puck.Logger.Warn("A file was modified", "path", filename)
+				}
+				if event.Has(fsnotify.Create) {
+					puck.Logger.Warn("A file was created", "path", filename)
+				}
+				if event.Has(fsnotify.Remove) {
+					puck.Logger.Warn("A file was removed", "path", filename)
+				}
+				if event.Has(fsnotify.Rename) {
+					puck.Logger.Warn("A file was renamed", "path", filename)
+				}
+				changes[yunyun.FullPathFile(event.Name)] |= event.Op
+				debounce.Reset(watcherDebounce)
+			case <-debounce.C:
+				w.apply(changes)
+				changes = make(map[yunyun.FullPathFile]fsnotify.Op)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					puck.Logger.Warn("Watcher is leaving")
+					return
+				}
+				puck.Logger.Error("Watcher", "err", err)
+			}
+		}
+	}()
+
+	puck.Logger.Print("Press Ctrl-C to stop the server")
+	// Block main goroutine forever.
+	<-make(chan struct{})
+}
+
+// watch starts watching the directory and all of its project subdirectories.
+func (w *siteWatcher) watch(root yunyun.FullPathDir) {
+	for _, dir := range hizuru.FindDirectories(w.conf, root) {
+		if _, ok := w.dirs[dir]; ok {
+			continue
+		}
+		if err := w.watcher.Add(string(dir)); err != nil {
+			puck.Logger.Error("Watching directory", "path", dir, "err", err)
+			continue
+		}
+		w.dirs[dir] = struct{}{}
+	}
+}
+
+// unwatch stops watching the directory and all of its subdirectories.
+func (w *siteWatcher) unwatch(root yunyun.FullPathDir) {
+	for dir := range w.dirs {
+		if dir != root && !strings.HasPrefix(string(dir), string(root)+string(filepath.Separator)) {
+			continue
+		}
+		// Removed directories are already unwatched by the system.
+		_ = w.watcher.Remove(string(dir))
+		delete(w.dirs, dir)
+	}
+}
+
+// rewatch watches the directories of the current config, the ones that
+// are no longer a part of the project are unwatched.
+func (w *siteWatcher) rewatch() {
+	current := make(map[yunyun.FullPathDir]struct{})
+	for _, dir := range hizuru.FindDirectories(w.conf, yunyun.FullPathDir(w.conf.Runtime.WorkDir)) {
+		current[dir] = struct{}{}
+	}
+	for dir := range w.dirs {
+		if _, ok := current[dir]; !ok {
+			_ = w.watcher.Remove(string(dir))
+			delete(w.dirs, dir)
+		}
+	}
+	w.watch(yunyun.FullPathDir(w.conf.Runtime.WorkDir))
+	// The config's directory might have been one of the unwatched.
+	if err := w.watcher.Add(filepath.Dir(w.conf.Runtime.ConfigFile)); err != nil {
+		puck.Logger.Warn("Couldn't watch the config", "path", w.conf.Runtime.ConfigFile, "err", err)
+	}
+}
+
+// isIgnored returns true if the change doesn't concern the site, like hidden
+// files, the build outputs, or files that darkness generates itself.
+func (w *siteWatcher) isIgnored(file yunyun.FullPathFile) bool {
+	if string(file) == w.conf.Runtime.ConfigFile {
+		return false
+	}
+	relative := string(w.conf.Runtime.WorkDir.Rel(file))
+	for _, part := range strings.Split(filepath.ToSlash(relative), "/") {
+		if strings.HasPrefix(part, ".") {
+			return true
+		}
+	}
+	if w.conf.Project.IsTemplate(file) {
+		return false
+	}
+	generated := []yunyun.RelativePathDir{w.conf.Project.DarknessVendorDirectory, w.conf.Project.DarknessPreviewDirectory}
+	if w.conf.Project.HasOutputDirectory() {
+		generated = append(generated, w.conf.Project.OutputDirectory)
+	} else if w.conf.Project.IsOutput(relative) {
+		return true
+	}
+	for _, dir := range generated {
+		if relative == string(dir) || strings.HasPrefix(relative, string(dir)+"/") {
+			return true
+		}
+	}
+	// Feeds, sitemaps, search indices, and link graphs are written by the build itself.
+	if w.conf.IsGenerated(yunyun.RelativePathFile(relative)) {
+		return true
+	}
+	return w.conf.Project.ExcludeEnabled && w.conf.Project.ExcludeRegex.MatchString(string(file))
+}
+
+// apply rebuilds whatever the changes touched and tells the browsers to
+// reload. Only the changed pages are rebuilt, while config and template
+// changes, new directories, or removed pages result in a full build.
+func (w *siteWatcher) apply(changes map[yunyun.FullPathFile]fsnotify.Op) {
+	pages := make([]yunyun.FullPathFile, 0, len(changes))
+	config, full, styles, assets, removed := false, false, false, false, false
+	for file := range changes {
+		info, err := os.Stat(string(file))
+		switch {
+		case string(file) == w.conf.Runtime.ConfigFile:
+			config = true
+		case w.conf.Project.IsTemplate(file):
+			// Templates shape every page.
+			full = true
+		case err != nil:
+			// Whatever is gone shouldn't leave its outputs behind, and
+			// feeds, indices, and other pages shouldn't link to removed pages.
+			if w.remove(file) {
+				full = true
+			}
+			removed = true
+		case info.IsDir():
+			w.watch(yunyun.FullPathDir(file))
+			full = true
+		case w.conf.Project.IsInput(string(file)):
+			pages = append(pages, file)
+		case filepath.Ext(string(file)) == ".css":
+			styles = true
+		default:
+			assets = true
+		}
+	}
+
+	if config {
+		if err := alpha.CheckConfig(w.options); err != nil {
+			puck.Logger.Error("Config is broken, not reloading", "err", err)
+			return
+		}
+		puck.Logger.Warn("Reloading the config", "path", w.conf.Runtime.ConfigFile)
+		// The old config is left untouched, the server switches over to the new one.
+		old := w.conf
+		w.conf = alpha.BuildConfig(w.options)
+		w.site.Swap(w.conf)
+		// Outputs of the old config would be left behind in the wrong place.
+		if old.Project.OutputDirectory != w.conf.Project.OutputDirectory {
+			removeOutputFiles(old)
+		}
+		// Excluded directories might have changed.
+		w.rewatch()
+		full = true
+	}
+
+	var err error
+	switch {
+	case full:
+		err = build(w.conf)
+	case len(pages) > 0:
+		err = rebuild(w.conf, pages)
+		// Static files changed together with pages still need copying.
+		if styles || assets {
+			copyAssets(w.conf)
+		}
+	case assets:
+		copyAssets(w.conf)
+	case styles:
+		// Stylesheets don't need a rebuild, only a fresh copy.
+		copyAssets(w.conf)
+		w.reload.broadcast(reloadEventStyles)
+		return
+	case !removed:
+		return
+	}
+	// Pages are still there even if feeds or sitemaps failed, so keep serving.
+	if err != nil {
+		puck.Logger.Error("Rebuilding", "err", err)
+	}
+	w.reload.broadcast(reloadEventPage)
+}
+
+// remove cleans up after a file or a directory that was removed, returns
+// true if pages might have been removed with it.
+func (w *siteWatcher) remove(file yunyun.FullPathFile) bool {
+	targets := []string{}
+	pages := false
+	switch _, isDir := w.dirs[yunyun.FullPathDir(file)]; {
+	case isDir:
+		w.unwatch(yunyun.FullPathDir(file))
+		pages = true
+		// Without an output directory, outputs were removed with it.
+		if !w.conf.Project.HasOutputDirectory() {
+			return pages
+		}
+		targets = append(targets, string(w.conf.Project.SourceToOutput(file)))
+	case w.conf.Project.IsInput(string(file)):
+		targets = w.conf.Project.InputFilenameToOutputs(file)
+		pages = true
+	case w.conf.Project.HasOutputDirectory():
+		targets = append(targets, string(w.conf.Project.SourceToOutput(file)))
+	}
+	for _, target := range targets {
+		if err := os.RemoveAll(target); err != nil {
+			puck.Logger.Error("Removing output of a removed file", "path", target, "err", err)
+		}
+	}
+	return pages
+}
@@ -0,0 +1,44 @@
+// Package testutil has the fixtures shared by the tests of darkness.
+package testutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+)
+
+// DefaultConfig is the config of projects that don't bring their own.
+const DefaultConfig = "url = \"https://example.com\"\n"
+
+// Project writes the files into a temporary directory and returns the
+// config of the project there. The files are keyed by their paths relative
+// to the project, darkness.toml is `DefaultConfig` if it's not among them.
+func Project(t testing.TB, files map[string]string) *alpha.DarknessConfig {
+	t.Helper()
+	dir := t.TempDir()
+	if _, ok := files["darkness.toml"]; !ok {
+		files = withFile(files, "darkness.toml", DefaultConfig)
+	}
+	for name, data := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return alpha.BuildConfig(alpha.Options{DarknessConfig: filepath.Join(dir, "darkness.toml"), WorkDir: dir})
+}
+
+// withFile returns a copy of the files with the file added.
+func withFile(files map[string]string, name, data string) map[string]string {
+	added := make(map[string]string, len(files)+1)
+	for file, contents := range files {
+		added[file] = contents
+	}
+	added[name] = data
+	return added
+}
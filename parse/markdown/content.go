@@ -0,0 +1,186 @@
+package markdown
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/thecsw/darkness/emilia/puck"
+	"github.com/thecsw/darkness/yunyun"
+	"github.com/thecsw/gana"
+)
+
+// isHeader returns a non-nil object if the line is a header
+func isHeader(line string) *yunyun.Content {
+	matches := headingRegexp.FindStringSubmatch(line)
+	// Not a header
+	if matches == nil {
+		return nil
+	}
+	// Is a header
+	return &yunyun.Content{
+		Type:         yunyun.TypeHeading,
+		HeadingLevel: uint32(len(matches[1])),
+		Heading:      matches[2],
+	}
+}
+
+// getLink returns a non-nil object if the line is a standalone link or image.
+func getLink(line string) *yunyun.Content {
+	matches := standaloneLinkRegexp.FindStringSubmatch(strings.TrimSpace(line))
+	// Extraction didn't yield any results.
+	if matches == nil {
+		return nil
+	}
+	link := &yunyun.Content{
+		Type:            yunyun.TypeLink,
+		Link:            matches[3],
+		LinkTitle:       matches[2],
+		LinkDescription: matches[4],
+	}
+	// Images are always images, even if the extension doesn't say so.
+	if matches[1] == "!" && !yunyun.ImageExtRegexp.MatchString(link.Link) {
+		link.Attributes = "image"
+	}
+	if link.LinkDescription == "" {
+		link.LinkDescription = link.LinkTitle
+	}
+	return link
+}
+
+// isAttentionBlock returns *Content object if we have fonud an attention block
+// with filled values, nil otherwise.
+func isAttentionBlock(line string) *yunyun.Content {
+	matches := attentionBlockRegexp.FindAllStringSubmatch(line, 1)
+	if len(matches) < 1 {
+		return nil
+	}
+	return &yunyun.Content{
+		Type:           yunyun.TypeAttentionText,
+		AttentionTitle: matches[0][1],
+		AttentionText:  matches[0][2],
+	}
+}
+
+// isTable returns true if we are currently reading a table, false otherwise.
+func isTable(line string) bool {
+	return strings.HasPrefix(line, "|")
+}
+
+// splitTableRow splits the table row into trimmed cells, escaped pipes
+// and pipes inside of code spans do not split the cells.
+func splitTableRow(line string) []string {
+	line = strings.TrimSuffix(strings.TrimPrefix(line, "|"), "|")
+	cells := make([]string, 0, 4)
+	cell, inCode, escaped := strings.Builder{}, false, false
+	for _, r := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '`':
+			inCode = !inCode
+		case r == '|' && !inCode:
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+			continue
+		}
+		cell.WriteRune(r)
+	}
+	cells = append(cells, strings.TrimSpace(cell.String()))
+	// Escaped pipes are just pipes.
+	for i := range cells {
+		cells[i] = strings.ReplaceAll(cells[i], `\|`, "|")
+	}
+	return cells
+}
+
+// isFenceBegin returns true if the line opens a fenced code block.
+func isFenceBegin(line string) bool {
+	return strings.HasPrefix(line, fenceBackticks) || strings.HasPrefix(line, fenceTildes)
+}
+
+// isFenceEnd returns true if the line closes the block opened by `fence`.
+func isFenceEnd(line, fence string) bool {
+	return strings.HasPrefix(line, fence) && strings.Trim(line, fence[:1]) == ""
+}
+
+// splitContainer returns the name and the arguments of a `:::` container.
+func splitContainer(line string) (string, string) {
+	line = strings.TrimSpace(strings.TrimLeft(line, ":"))
+	name, arguments, _ := strings.Cut(line, " ")
+	return strings.ToLower(name), strings.TrimSpace(arguments)
+}
+
+// extractGalleryOptions extracts the gallery path and the number of
+// images per row from `::: gallery path=photos num=3`.
+func extractGalleryOptions(arguments string) (string, uint) {
+	path, width := "", defaultGalleryImagesPerRow
+	for _, matches := range customBlockOptionRegexp.FindAllStringSubmatch(arguments, -1) {
+		switch matches[1] {
+		case "path":
+			path = matches[2]
+		case "num":
+			num, err := strconv.Atoi(matches[2])
+			if err != nil || num < 1 {
+				puck.Logger.Warnf("gallery width should be a positive number, defaulting to %d", defaultGalleryImagesPerRow)
+				continue
+			}
+			width = uint(num)
+		}
+	}
+	return path, width
+}
+
+// extractFootnotes removes footnote definitions from the lines and
+// returns them as a map of labels to footnote texts. Fenced code blocks
+// are left untouched, the same way `Do` reads them.
+func extractFootnotes(lines []string) ([]string, map[string]string) {
+	footnotes := make(map[string]string)
+	kept := make([]string, 0, len(lines))
+	// fence is the fence that opened the current code block.
+	fence := ""
+	for _, rawLine := range lines {
+		line := strings.TrimSpace(rawLine)
+		switch {
+		case fence != "":
+			if isFenceEnd(line, fence) {
+				fence = ""
+			}
+		case isFenceBegin(line):
+			fence = line[:gana.CountRunesLeft[uint](line, rune(line[0]))]
+		default:
+			if matches := footnoteDefinitionRegexp.FindStringSubmatch(line); matches != nil {
+				footnotes[matches[1]] = strings.TrimSpace(matches[2])
+				continue
+			}
+		}
+		kept = append(kept, rawLine)
+	}
+	return kept, footnotes
+}
+
+// readFrontMatter reads the `---` delimited front matter (if present) and
+// runs the matching actions, returns the lines that follow it.
+func readFrontMatter(lines []string, actions map[string]func(string)) []string {
+	if len(lines) < 1 || strings.TrimSpace(lines[0]) != frontMatterDelimiter {
+		return lines
+	}
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) != frontMatterDelimiter {
+			continue
+		}
+		for _, line := range lines[1:i] {
+			key, value, found := strings.Cut(strings.TrimSpace(line), ":")
+			if !found {
+				continue
+			}
+			if action, ok := actions[strings.ToLower(strings.TrimSpace(key))]; ok {
+				action(strings.Trim(strings.TrimSpace(value), `"'`))
+			}
+		}
+		return lines[i+1:]
+	}
+	// Front matter was never closed, so treat it as regular content.
+	return lines
+}
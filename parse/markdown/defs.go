@@ -0,0 +1,81 @@
+package markdown
+
+import (
+	"regexp"
+)
+
+const (
+	frontMatterDelimiter = "---"
+	commentBegin         = "<!--"
+	commentEnd           = "-->"
+	containerDelimiter   = ":::"
+	fenceBackticks       = "```"
+	fenceTildes          = "~~~"
+	quotePrefix          = ">"
+
+	containerQuote   = "quote"
+	containerCenter  = "center"
+	containerDetails = "details"
+	containerGallery = "gallery"
+
+	optionDropCap    = "drop_cap"
+	optionCaption    = "caption"
+	optionDate       = "date"
+	optionUpdated    = "updated"
+	optionTitle      = "title"
+	optionHtmlHead   = "html_head"
+	optionOptions    = "options"
+	optionAttributes = "attr_darkness"
+	optionHtmlTags   = "html_tags"
+	optionAuthor     = "author"
+	optionEnclosure  = "enclosure"
+	optionTags       = "tags"
+	optionSeries     = "series"
+
+	// rawHtmlFenceLanguage is the pandoc-style raw attribute, which
+	// marks fenced code blocks that should be exported as raw html.
+	rawHtmlFenceLanguage = "{=html}"
+)
+
+var (
+	// headingRegexp is the regexp for matching ATX headings.
+	headingRegexp = regexp.MustCompile(`^(#{1,6})\s+(.*?)(?:\s+#+)?\s*$`)
+	// setextRegexp is the regexp for matching setext heading underlines.
+	setextRegexp = regexp.MustCompile(`^(=+|-+)\s*$`)
+	// horizontalLineRegexp is the regexp for matching thematic breaks.
+	horizontalLineRegexp = regexp.MustCompile(`^(?:(?:-\s*){3,}|(?:\*\s*){3,}|(?:_\s*){3,})$`)
+	// listItemRegexp is the regexp for matching list items (ordered and unordered).
+	listItemRegexp = regexp.MustCompile(`^(\s*)(?:[-*+]|\d{1,9}[.)])\s+(.*)$`)
+	// tableDelimiterRegexp is the regexp for matching the table header delimiter row.
+	tableDelimiterRegexp = regexp.MustCompile(`^\|?\s*:?-+:?\s*(?:\|\s*:?-+:?\s*)*\|?$`)
+	// attentionBlockRegexp is the regexp for matching attention blocks.
+	attentionBlockRegexp = regexp.MustCompile(`^(WARNING|NOTE|TIP|IMPORTANT|CAUTION):\s*(.+)`)
+	// alertRegexp is the regexp for matching GitHub-style alerts in block quotes.
+	alertRegexp = regexp.MustCompile(`^\[!(WARNING|NOTE|TIP|IMPORTANT|CAUTION)\]\s*(.*)$`)
+	// directiveRegexp is the regexp for matching `<!-- key: value -->` directives,
+	// which are markdown's equivalent of orgmode's `#+key: value`.
+	directiveRegexp = regexp.MustCompile(`^<!--\s*([a-z_]+)(?::\s*(.*?))?\s*-->$`)
+	// footnoteDefinitionRegexp is the regexp for matching footnote definitions.
+	footnoteDefinitionRegexp = regexp.MustCompile(`^\[\^([^\]\s]+)\]:\s*(.+)$`)
+	// footnoteReferenceRegexp is the regexp for matching footnote references.
+	footnoteReferenceRegexp = regexp.MustCompile(`\[\^([^\]\s]+)\]`)
+	// standaloneLinkRegexp is the regexp for matching a link or an image that
+	// sits on its own line (to be treated as an embed).
+	standaloneLinkRegexp = regexp.MustCompile(`^(!?)\[([^\]]*)\]\(\s*<?([^\s)>]+)>?(?:\s+"([^"]*)")?\s*\)$`)
+	// customBlockOptionRegexp is the regexp for container options, like `path=photos`.
+	customBlockOptionRegexp = regexp.MustCompile(`:?(path|num)[= ](\S+)`)
+)
+
+// Inline markup regexes, which are used to translate markdown's inline
+// formatting into yunyun's markings.
+var (
+	codeSpanRegexp      = regexp.MustCompile("(`+)(.+?)(`+)")
+	imageInlineRegexp   = regexp.MustCompile(`!\[([^\]]*)\]\(\s*<?([^\s)>]+)>?(?:\s+"([^"]*)")?\s*\)`)
+	linkInlineRegexp    = regexp.MustCompile(`\[([^\]]+)\]\(\s*<?([^\s)>]+)>?(?:\s+"([^"]*)")?\s*\)`)
+	autolinkRegexp      = regexp.MustCompile(`<(https?://[^\s>]+)>`)
+	boldStarsRegexp     = regexp.MustCompile(`\*\*(\S|\S.*?\S)\*\*`)
+	boldUnderRegexp     = regexp.MustCompile(`__(\S|\S.*?\S)__`)
+	italicStarRegexp    = regexp.MustCompile(`\*(\S|\S[^*]*?\S)\*`)
+	italicUnderRegexp   = regexp.MustCompile(`_(\S|\S.*?\S)_`)
+	strikethroughRegexp = regexp.MustCompile(`~~(\S|\S.*?\S)~~`)
+)
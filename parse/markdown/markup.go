@@ -0,0 +1,178 @@
+package markdown
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/thecsw/darkness/yunyun"
+)
+
+const (
+	// placeholderStart and placeholderEnd surround indices of text
+	// fragments that should be protected from further translations.
+	placeholderStart = "\x02"
+	placeholderEnd   = "\x03"
+	// boldMarker, italicMarker, and strikethroughMarker temporarily mark
+	// the translated text, so the markings don't get confused with
+	// single-star italics or with plain text characters.
+	boldMarker          = "\x04"
+	italicMarker        = "\x05"
+	strikethroughMarker = "\x06"
+	// escapedMarkingStart is where the characters that stand in for the
+	// escaped marking characters start, in the order of `markingCharacters`.
+	escapedMarkingStart = '\x0e'
+	// markingCharacters are the characters that yunyun uses for markings.
+	markingCharacters = "*/=~+_^"
+)
+
+// markerReplacer turns the temporary markers into yunyun's markings.
+var markerReplacer = strings.NewReplacer(boldMarker, "*", italicMarker, "/", strikethroughMarker, "+")
+
+// placeholderRegexp matches placeholders left by `protector`.
+var placeholderRegexp = regexp.MustCompile(placeholderStart + `(\d+)` + placeholderEnd)
+
+// backslashEscapeRegexp matches backslash-escaped punctuation.
+var backslashEscapeRegexp = regexp.MustCompile("\\\\([!\"#$%&'()*+,\\-./:;<=>?@\\[\\]^_`{|}~])")
+
+// protector stores text fragments that should survive all the
+// translations untouched and be restored in the very end.
+type protector []string
+
+// protect stores the fragment and returns its placeholder.
+func (p *protector) protect(what string) string {
+	*p = append(*p, what)
+	return placeholderStart + strconv.Itoa(len(*p)-1) + placeholderEnd
+}
+
+// restore replaces all placeholders with their original fragments.
+func (p protector) restore(text string) string {
+	// Fragments may be nested (like code spans inside link texts),
+	// so keep going until nothing is left to be restored.
+	for placeholderRegexp.MatchString(text) {
+		text = placeholderRegexp.ReplaceAllStringFunc(text, func(what string) string {
+			i, _ := strconv.Atoi(strings.Trim(what, placeholderStart+placeholderEnd))
+			return p[i]
+		})
+	}
+	return text
+}
+
+// toYunyunMarkup translates markdown's inline formatting into yunyun's
+// markings, so the exporters can process the text like any other.
+func toYunyunMarkup(text string, footnotes map[string]string) string {
+	p := make(protector, 0, 4)
+
+	// Code spans are verbatim, nothing inside of them should change.
+	text = codeSpanRegexp.ReplaceAllStringFunc(text, func(what string) string {
+		submatches := codeSpanRegexp.FindStringSubmatch(what)
+		if submatches[1] != submatches[3] {
+			return what
+		}
+		code := strings.TrimSpace(submatches[2])
+		delimiter := "="
+		if strings.Contains(code, "=") {
+			delimiter = "~"
+		}
+		return p.protect(delimiter + code + delimiter)
+	})
+
+	// Escaped punctuation should be shown as is, even by yunyun. Escaped
+	// marking characters stand in until the emphasis is converted, so the
+	// guards are added with the whole text in mind.
+	text = backslashEscapeRegexp.ReplaceAllStringFunc(text, func(what string) string {
+		if i := strings.Index(markingCharacters, what[1:]); i >= 0 {
+			return string(escapedMarkingStart + rune(i))
+		}
+		return p.protect(what[1:])
+	})
+
+	// Inline images and links become yunyun links, where the url is
+	// protected and the text is translated on its own. Yunyun's inline
+	// links have no titles, so they're dropped.
+	text = imageInlineRegexp.ReplaceAllStringFunc(text, func(what string) string {
+		submatches := imageInlineRegexp.FindStringSubmatch(what)
+		return p.protect(formLink(submatches[2], convertEmphasis(submatches[1])))
+	})
+	text = linkInlineRegexp.ReplaceAllStringFunc(text, func(what string) string {
+		submatches := linkInlineRegexp.FindStringSubmatch(what)
+		return p.protect(formLink(submatches[2], convertEmphasis(submatches[1])))
+	})
+	text = autolinkRegexp.ReplaceAllStringFunc(text, func(what string) string {
+		link := strings.Trim(what, "<>")
+		return p.protect(formLink(link, link))
+	})
+
+	// Footnote references get replaced with inline footnotes.
+	text = footnoteReferenceRegexp.ReplaceAllStringFunc(text, func(what string) string {
+		label := footnoteReferenceRegexp.FindStringSubmatch(what)[1]
+		footnote, ok := footnotes[label]
+		if !ok {
+			return what
+		}
+		return p.protect("[fn:: " + toYunyunMarkup(footnote, nil) + "]")
+	})
+
+	return p.restore(convertEmphasis(text))
+}
+
+// convertEmphasis translates bold, italic, and strikethrough text. All
+// the other characters that yunyun would see as markings are kept as is.
+func convertEmphasis(text string) string {
+	text = replaceEmphasis(text, boldStarsRegexp, boldMarker)
+	text = replaceEmphasis(text, boldUnderRegexp, boldMarker)
+	text = replaceEmphasis(text, italicStarRegexp, italicMarker)
+	text = replaceEmphasis(text, italicUnderRegexp, italicMarker)
+	text = replaceEmphasis(text, strikethroughRegexp, strikethroughMarker)
+	text = strings.Map(func(r rune) rune {
+		if r >= escapedMarkingStart && r < escapedMarkingStart+rune(len(markingCharacters)) {
+			return rune(markingCharacters[r-escapedMarkingStart])
+		}
+		return r
+	}, text)
+	return markerReplacer.Replace(yunyun.Literal(text))
+}
+
+// replaceEmphasis surrounds the text of the emphasis with the marker, but
+// only where yunyun accepts the borders of the marking, like in the middle
+// of a word it doesn't. Those are left alone, so `2*3*4` stays as it is.
+func replaceEmphasis(text string, emphasis *regexp.Regexp, marker string) string {
+	replaced := &strings.Builder{}
+	// from is where we look for the next emphasis, done is how much is replaced.
+	from, done := 0, 0
+	for {
+		match := emphasis.FindStringSubmatchIndex(text[from:])
+		if match == nil {
+			break
+		}
+		start, end := from+match[0], from+match[1]
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if (start > 0 && !isEmphasisBorder(before, yunyun.IsMarkingBorderLeft)) ||
+			(end < len(text) && !isEmphasisBorder(after, yunyun.IsMarkingBorderRight)) {
+			// Another emphasis could still start right after this one did.
+			_, size := utf8.DecodeRuneInString(text[start:])
+			from = start + size
+			continue
+		}
+		replaced.WriteString(text[done:start] + marker + text[from+match[2]:from+match[3]] + marker)
+		from, done = end, end
+	}
+	return replaced.String() + text[done:]
+}
+
+// isEmphasisBorder returns true if an emphasis can be next to the rune,
+// which also includes the markers of other emphases, as they nest.
+func isEmphasisBorder(r rune, isBorder func(rune) bool) bool {
+	return isBorder(r) || strings.ContainsRune(boldMarker+italicMarker+strikethroughMarker, r)
+}
+
+// formLink builds a yunyun link out of its parts.
+func formLink(link, text string) string {
+	if text == "" {
+		text = link
+	}
+	return fmt.Sprintf("[[%s][%s]]", link, text)
+}
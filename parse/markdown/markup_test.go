@@ -0,0 +1,148 @@
+package markdown
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/export"
+	"github.com/thecsw/darkness/export/gemini"
+	"github.com/thecsw/darkness/export/html"
+	"github.com/thecsw/darkness/export/json"
+	"github.com/thecsw/darkness/internal/testutil"
+	"github.com/thecsw/darkness/yunyun"
+)
+
+// wj is the invisible guard that keeps yunyun from seeing markings.
+const wj = "⁠"
+
+func TestToYunyunMarkup(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"bold stars", "**bold** text", "*bold* text"},
+		{"bold underscores", "__bold__ text", "*bold* text"},
+		{"italic star", "an *italic* word", "an /italic/ word"},
+		{"italic underscore", "an _italic_ word", "an /italic/ word"},
+		{"strikethrough", "~~gone~~ now", "+gone+ now"},
+		{"snake case", "call some_long_name now", "call some_long_name now"},
+		{"intraword star", "2*3*4", "2*3*4"},
+		{"intraword stars", "un**believ**able", "un**believ**able"},
+		{"intraword strikethrough", "a~~b~~c", "a~~b~~c"},
+		{"intraword then italic", "a*b*c *d*", "a*b*c /d/"},
+		{"adjacent underscores", "_a_ _b_", "/a/ /b/"},
+		{"nested emphasis", "**a *b* c**", "*a /b/ c*"},
+		{"emphasis before punctuation", "*this*, then", "/this/, then"},
+		{"code span", "run `go test`", "run =go test="},
+		{"code span with equals", "set `a = b` first", "set ~a = b~ first"},
+		{"code span keeps markup", "see `**not bold**`", "see =**not bold**="},
+		{"escaped stars", `\*not bold\*`, wj + "*not bold*" + wj},
+		{"escaped underscores", `\_x\_`, wj + "_x_" + wj},
+		{"lone escaped star", `a \* b`, "a * b"},
+		{"escaped brackets", `\[not a link\]`, "[not a link]"},
+		{"plain slashes", "and/or x/y", "and/or x/y"},
+		{"plain paths", "the /tmp/ dir", "the " + wj + "/tmp/" + wj + " dir"},
+		{"plain pluses", "1 + 2 + 3", "1 + 2 + 3"},
+		{"plain tildes", "C++ ~tilde~", "C++ " + wj + "~tilde~" + wj},
+		{"link", "[text](https://example.com)", "[[https://example.com][text]]"},
+		{"link with emphasis", "[**bold**](a.html)", "[[a.html][*bold*]]"},
+		{"autolink", "<https://example.com>", "[[https://example.com][https://example.com]]"},
+		{"url in text", "see https://example.com/a_b", "see https://example.com/a_b"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := toYunyunMarkup(test.text, nil); got != test.want {
+				t.Errorf("toYunyunMarkup(%q) = %q, want %q", test.text, got, test.want)
+			}
+		})
+	}
+}
+
+func TestInlineLinksHtml(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"link", "Visit [Go](https://go.dev) now.", `Visit <a href="https://go.dev" title="">Go</a> now.`},
+		// Inline links have no titles, they shouldn't end up in the text.
+		{"link with title", `Visit [Go](https://go.dev "the site") now.`, `Visit <a href="https://go.dev" title="">Go</a> now.`},
+		{"image with title", `See ![a cat](cat.png "Caption") here.`, `See <a href="cat.png" title="">a cat</a> here.`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			body := html.ExporterHtml{Config: &alpha.DarknessConfig{}}.Body(parse(test.text))
+			if !strings.Contains(body, test.want) {
+				t.Errorf("body of %q is\n%s\nwant it to have %s", test.text, body, test.want)
+			}
+		})
+	}
+}
+
+func TestLiteralGuardsAreRemoved(t *testing.T) {
+	const text = `C++ ~tilde~ in the /tmp/ dir, \*really\*`
+	const want = "C++ ~tilde~ in the /tmp/ dir, *really*"
+	page := parse(text)
+	body := html.ExporterHtml{Config: &alpha.DarknessConfig{}}.Body(page)
+	if !strings.Contains(body, want) {
+		t.Errorf("body of %q is\n%q\nwant it to have %q", text, body, want)
+	}
+	// Descriptions in meta tags and feeds are plain text.
+	if got := yunyun.RemoveFormatting(page.Contents[0].Paragraph); got != want {
+		t.Errorf("RemoveFormatting(%q) = %q, want %q", page.Contents[0].Paragraph, got, want)
+	}
+	// Other outputs see the parsed text as it is.
+	conf := testutil.Project(t, nil)
+	for name, exporter := range map[string]export.Exporter{
+		"gemini": gemini.ExporterGemini{Config: conf},
+		"json":   json.ExporterJson{Config: conf},
+	} {
+		data, err := io.ReadAll(exporter.Do(page))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if output := string(data); !strings.Contains(output, want) || strings.Contains(output, wj) {
+			t.Errorf("%s output of %q is\n%q\nwant it to have %q", name, text, output, want)
+		}
+	}
+}
+
+func TestToYunyunMarkupFootnotes(t *testing.T) {
+	footnotes := map[string]string{"1": "A *note*."}
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Text[^1] here.", "Text[fn:: A /note/.] here."},
+		{"Text[^2] here.", "Text[^2] here."},
+	}
+	for _, test := range tests {
+		if got := toYunyunMarkup(test.text, footnotes); got != test.want {
+			t.Errorf("toYunyunMarkup(%q) = %q, want %q", test.text, got, test.want)
+		}
+	}
+}
+
+func TestLiteralTextHasNoMarkings(t *testing.T) {
+	yunyun.ActiveMarkings.BuildRegex()
+	tests := []string{
+		`\*not bold\*`,
+		`\_not underlined\_`,
+		`\~not verbatim\~ or \=this\=`,
+		`\+not struck\+`,
+		"read and/or write the /tmp/ dir",
+		"1 + 2 + 3 and +4+",
+		"x^{{2}} stays as is",
+	}
+	for _, text := range tests {
+		got := toYunyunMarkup(text, nil)
+		for _, marking := range yunyun.SpecialTextMarkups {
+			if marking.MatchString(got) {
+				t.Errorf("toYunyunMarkup(%q) = %q, which yunyun sees as %s", text, got, marking)
+			}
+		}
+	}
+}
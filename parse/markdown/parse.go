@@ -0,0 +1,447 @@
+package markdown
+
+import (
+	"strings"
+
+	"github.com/thecsw/darkness/emilia"
+	"github.com/thecsw/darkness/emilia/puck"
+	"github.com/thecsw/darkness/yunyun"
+	"github.com/thecsw/gana"
+)
+
+const (
+	defaultGalleryImagesPerRow uint = 3
+)
+
+// Do parses the input string and returns a page.
+func (p ParserMarkdown) Do(
+	filename yunyun.RelativePathFile,
+	data string,
+) *yunyun.Page {
+	defer puck.Stopwatch("Parsed", "page", filename).Record()
+
+	// Split the data into lines and collect the footnote definitions,
+	// since references can come before them.
+	lines, footnotes := extractFootnotes(strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n"))
+
+	page := yunyun.NewPage(
+		yunyun.WithFilename(filename),
+		yunyun.WithLocation(yunyun.RelativePathTrim(filename)),
+		yunyun.WithContents(make([]*yunyun.Content, 0, 32)),
+	)
+	page.Author = p.Config.RSS.DefaultAuthor
+
+	// currentFlags uses flags to set options
+	currentFlags := yunyun.Bits(0)
+	// caption is the current caption we can read
+	caption := ""
+	// attributes is the attributes for the current content.
+	attributes := ""
+	// additionalContext is the current details' summary
+	additionalContext := ""
+	// galleryPath stores the gallery's declared path
+	galleryPath := ""
+	// galleryWidth dictates on how many items per row we will have
+	galleryWidth := defaultGalleryImagesPerRow
+	// User can provide custom style for an image (like resizing).
+	customHtmlTags := ""
+	// containers is the stack of currently opened `:::` containers.
+	containers := make([]string, 0, 2)
+
+	// fence is the fence that opened the current code block.
+	fence := ""
+	// sourceCodeLang is the language of the source code block
+	sourceCodeLang := ""
+	// paragraph, listItems, tableRows, quoteLines, and blockLines are
+	// the buffers for blocks that are currently being read.
+	paragraph := make([]string, 0, 8)
+	listItems := make([]yunyun.ListItem, 0, 8)
+	tableRows := make([][]string, 0, 8)
+	quoteLines := make([]string, 0, 8)
+	blockLines := make([]string, 0, 8)
+	// listItemInitialIndent is the initial indent of the list item
+	listItemInitialIndent := uint8(0)
+	// inComment is true when we are inside of a multi-line html comment.
+	inComment := false
+
+	// optionsStrings will get populated as the page is being scanned
+	// and then parsed out before leaving this parser.
+	optionsStrings := ""
+	defer emilia.FillAccoutrement(p.Config.Website.Tombs, &optionsStrings, page)
+
+	// Optional parsing to see if H.E. has been left on the first line
+	// as the date
+	defer fillHolosceneDate(page)
+
+	addFlag, removeFlag, _, hasFlag := yunyun.LatchFlags(&currentFlags)
+	// addContent is a helper function to add content to the page
+	addContent := func(content *yunyun.Content) {
+		content.Options = currentFlags
+		content.Summary = additionalContext
+		content.GalleryPath = yunyun.RelativePathDir(galleryPath)
+		content.GalleryImagesPerRow = galleryWidth
+		if len(content.Caption) < 1 {
+			content.Caption = caption
+		}
+		// Attributes from the directive add to the ones the content came with.
+		content.Attributes = strings.TrimSpace(content.Attributes + " " + attributes)
+		content.CustomHtmlTags = customHtmlTags
+		page.Contents = append(page.Contents, content)
+		additionalContext = ""
+		attributes = ""
+		customHtmlTags = ""
+		caption = ""
+	}
+	// markup translates inline markdown into yunyun markings.
+	markup := func(text string) string { return toYunyunMarkup(text, footnotes) }
+
+	directiveActions := map[string]func(value string){
+		optionDropCap:    func(value string) { addFlag(yunyun.InDropCapFlag) },
+		optionCaption:    func(value string) { caption = value },
+		optionTitle:      func(value string) { page.Title = markup(value) },
+		optionDate:       func(value string) { page.Date = value },
+		optionUpdated:    func(value string) { page.Updated = value },
+		optionHtmlHead:   func(value string) { page.HtmlHead = append(page.HtmlHead, value) },
+		optionOptions:    func(value string) { optionsStrings += value + " " },
+		optionAttributes: func(value string) { attributes = value },
+		optionAuthor:     func(value string) { page.Author = value },
+		optionHtmlTags:   func(value string) { customHtmlTags = value },
+		optionEnclosure:  func(value string) { page.Enclosure = yunyun.ParseEnclosure(value) },
+		optionTags:       func(value string) { page.AddTags(yunyun.ParseTags(value)...) },
+		optionSeries:     func(value string) { page.Series = yunyun.ParseSeries(value) },
+	}
+
+	// Front matter can only be declared on the very first line.
+	lines = readFrontMatter(lines, directiveActions)
+
+	// Yunyun's markings default to orgmode, which is what we translate to.
+	yunyun.ActiveMarkings.BuildRegex()
+
+	flushParagraph := func() {
+		if len(paragraph) < 1 {
+			return
+		}
+		text := strings.Join(paragraph, " ")
+		paragraph = paragraph[:0]
+		// Let's see if our paragraph is a standalone link
+		if link := getLink(text); link != nil {
+			link.LinkTitle = markup(link.LinkTitle)
+			addContent(link)
+			return
+		}
+		// Also check if this is an attention block, like "NOTE:..." or "WARNING:..."
+		if attention := isAttentionBlock(text); attention != nil {
+			attention.AttentionText = markup(attention.AttentionText)
+			addContent(attention)
+			return
+		}
+		addContent(&yunyun.Content{
+			Type:      yunyun.TypeParagraph,
+			Paragraph: markup(text),
+		})
+		// Reset the drop cap flag
+		removeFlag(yunyun.InDropCapFlag)
+	}
+	flushList := func() {
+		if len(listItems) < 1 {
+			return
+		}
+		for i := range listItems {
+			listItems[i].Text = markup(strings.TrimSpace(listItems[i].Text))
+		}
+		addContent(&yunyun.Content{
+			Type: yunyun.TypeList,
+			List: append([]yunyun.ListItem{}, listItems...),
+		})
+		listItems = listItems[:0]
+		listItemInitialIndent = 0
+	}
+	flushTable := func() {
+		if len(tableRows) < 1 {
+			return
+		}
+		for _, row := range tableRows {
+			for j := range row {
+				row[j] = markup(row[j])
+			}
+		}
+		addContent(&yunyun.Content{
+			Type:         yunyun.TypeTable,
+			Table:        append([][]string{}, tableRows...),
+			TableHeaders: hasFlag(yunyun.InTableHasHeadersFlag),
+		})
+		tableRows = tableRows[:0]
+		removeFlag(yunyun.InTableFlag | yunyun.InTableHasHeadersFlag)
+	}
+	flushQuote := func() {
+		if len(quoteLines) < 1 {
+			return
+		}
+		lines := quoteLines
+		quoteLines = quoteLines[:0]
+		// GitHub-style alerts are our attention blocks.
+		if matches := alertRegexp.FindStringSubmatch(gana.First(lines)); matches != nil {
+			text := strings.TrimSpace(matches[2] + " " + strings.Join(lines[1:], " "))
+			addContent(&yunyun.Content{
+				Type:           yunyun.TypeAttentionText,
+				AttentionTitle: matches[1],
+				AttentionText:  markup(text),
+			})
+			return
+		}
+		// Otherwise, every quoted paragraph is a quote.
+		alreadyQuoted := hasFlag(yunyun.InQuoteFlag)
+		addFlag(yunyun.InQuoteFlag)
+		for _, line := range append(lines, "") {
+			if line != "" {
+				paragraph = append(paragraph, line)
+				continue
+			}
+			flushParagraph()
+		}
+		if !alreadyQuoted {
+			removeFlag(yunyun.InQuoteFlag)
+		}
+	}
+	flushAll := func() {
+		flushParagraph()
+		flushList()
+		flushTable()
+		flushQuote()
+	}
+
+	// Loop through the lines
+	for _, rawLine := range lines {
+		// Trim the line from whitespaces
+		line := strings.TrimSpace(rawLine)
+
+		// If we are in a fenced block, wait for the closing fence.
+		if hasFlag(yunyun.InSourceCodeFlag) || hasFlag(yunyun.InRawHtmlFlag) {
+			if !isFenceEnd(line, fence) {
+				blockLines = append(blockLines, rawLine)
+				continue
+			}
+			isRawHtml := hasFlag(yunyun.InRawHtmlFlag)
+			removeFlag(yunyun.InSourceCodeFlag | yunyun.InRawHtmlFlag)
+			if isRawHtml {
+				addContent(&yunyun.Content{
+					Type:    yunyun.TypeRawHtml,
+					RawHtml: strings.Join(blockLines, "\n") + "\n",
+				})
+			} else {
+				addContent(&yunyun.Content{
+					Type:           yunyun.TypeSourceCode,
+					SourceCodeLang: sourceCodeLang,
+					SourceCode:     strings.TrimRight(strings.Join(blockLines, "\n"), "\n\t\r\f\b"),
+				})
+			}
+			removeFlag(yunyun.InRawHtmlFlagUnsafe | yunyun.InRawHtmlFlagResponsive)
+			blockLines = blockLines[:0]
+			continue
+		}
+
+		// Multi-line html comments are completely ignored.
+		if inComment {
+			inComment = !strings.Contains(line, commentEnd)
+			continue
+		}
+
+		// If we hit an empty line, end the whatever block we had
+		if line == "" {
+			flushAll()
+			continue
+		}
+
+		// Should we enter a fenced code block?
+		if isFenceBegin(line) {
+			flushAll()
+			fence = line[:gana.CountRunesLeft[uint](line, rune(line[0]))]
+			sourceCodeLang = strings.TrimSpace(line[len(fence):])
+			if strings.HasPrefix(sourceCodeLang, rawHtmlFenceLanguage[:len(rawHtmlFenceLanguage)-1]) {
+				addFlag(yunyun.InRawHtmlFlag)
+				if strings.Contains(sourceCodeLang, "unsafe") {
+					addFlag(yunyun.InRawHtmlFlagUnsafe)
+				} else if strings.Contains(sourceCodeLang, "responsive") || strings.Contains(sourceCodeLang, "iframe") {
+					addFlag(yunyun.InRawHtmlFlagResponsive)
+				}
+				continue
+			}
+			addFlag(yunyun.InSourceCodeFlag)
+			continue
+		}
+
+		// Directives are html comments with a special form, they act
+		// just like orgmode's options.
+		if matches := directiveRegexp.FindStringSubmatch(line); matches != nil {
+			if action, ok := directiveActions[matches[1]]; ok {
+				flushAll()
+				action(strings.TrimSpace(matches[2]))
+			}
+			continue
+		}
+		// Ignore the rest of html comments
+		if strings.HasPrefix(line, commentBegin) {
+			inComment = !strings.Contains(line, commentEnd)
+			continue
+		}
+
+		// Containers can be quotes, centers, details, and galleries.
+		if strings.HasPrefix(line, containerDelimiter) {
+			flushAll()
+			name, arguments := splitContainer(line)
+			// A bare delimiter closes the last opened container.
+			if name == "" {
+				if len(containers) < 1 {
+					continue
+				}
+				closing := gana.Last(containers)
+				containers = containers[:len(containers)-1]
+				switch closing {
+				case containerQuote:
+					removeFlag(yunyun.InQuoteFlag)
+				case containerCenter:
+					removeFlag(yunyun.InCenterFlag)
+				case containerDetails:
+					removeFlag(yunyun.InDetailsFlag)
+					addContent(&yunyun.Content{Type: yunyun.TypeDetails})
+				case containerGallery:
+					removeFlag(yunyun.InGalleryFlag)
+					galleryPath = ""
+					galleryWidth = defaultGalleryImagesPerRow
+				}
+				continue
+			}
+			containers = append(containers, name)
+			switch name {
+			case containerQuote:
+				addFlag(yunyun.InQuoteFlag)
+			case containerCenter:
+				addFlag(yunyun.InCenterFlag)
+			case containerDetails:
+				addFlag(yunyun.InDetailsFlag)
+				additionalContext = arguments
+				if additionalContext == "" {
+					additionalContext = "open for details"
+				}
+				addContent(&yunyun.Content{Type: yunyun.TypeDetails})
+			case containerGallery:
+				addFlag(yunyun.InGalleryFlag)
+				galleryPath, galleryWidth = extractGalleryOptions(arguments)
+			default:
+				puck.Logger.Warn("Unknown container", "name", name, "page", filename)
+			}
+			continue
+		}
+
+		// Now, we need to parse headings here
+		if header := isHeader(line); header != nil {
+			flushAll()
+			header.Heading = markup(header.Heading)
+			if header.HeadingLevel == 1 {
+				page.Title = header.Heading
+				continue
+			}
+			addContent(header)
+			continue
+		}
+
+		// Setext headings underline the previous paragraph.
+		if len(paragraph) > 0 && len(quoteLines) < 1 && setextRegexp.MatchString(line) {
+			text := markup(strings.Join(paragraph, " "))
+			paragraph = paragraph[:0]
+			if line[0] == '=' {
+				page.Title = text
+				continue
+			}
+			addContent(&yunyun.Content{
+				Type:         yunyun.TypeHeading,
+				HeadingLevel: 2,
+				Heading:      text,
+			})
+			continue
+		}
+
+		// Add a horizontal line divider
+		if horizontalLineRegexp.MatchString(line) {
+			flushAll()
+			addContent(&yunyun.Content{Type: yunyun.TypeHorizontalLine})
+			continue
+		}
+
+		// Block quotes are collected and then processed as a whole.
+		if strings.HasPrefix(line, quotePrefix) {
+			if len(quoteLines) < 1 {
+				flushAll()
+			}
+			quoteLines = append(quoteLines, strings.TrimSpace(strings.TrimPrefix(line, quotePrefix)))
+			continue
+		}
+		// Lazy continuation of the quote.
+		if len(quoteLines) > 0 {
+			quoteLines = append(quoteLines, line)
+			continue
+		}
+
+		// Lists can be nested with indentation.
+		if matches := listItemRegexp.FindStringSubmatch(rawLine); matches != nil {
+			flushParagraph()
+			flushTable()
+			indent := uint8(len(strings.ReplaceAll(matches[1], "\t", "    ")))
+			if len(listItems) < 1 {
+				listItemInitialIndent = indent
+			}
+			listItems = append(listItems, yunyun.ListItem{
+				Level: uint8(gana.Max(int(indent)-int(listItemInitialIndent), 0)/2 + 1),
+				Text:  matches[2],
+			})
+			continue
+		}
+		// Everything else that is not separated is a list item's continuation.
+		if len(listItems) > 0 {
+			listItems[len(listItems)-1].Text += " " + line
+			continue
+		}
+
+		// Tables are rows of cells delimited by pipes.
+		if isTable(line) {
+			flushParagraph()
+			addFlag(yunyun.InTableFlag)
+			// If it's a delimeter, mark the headers and move on
+			if tableDelimiterRegexp.MatchString(line) && strings.Contains(line, "-") {
+				if len(tableRows) == 1 {
+					addFlag(yunyun.InTableHasHeadersFlag)
+				}
+				continue
+			}
+			tableRows = append(tableRows, splitTableRow(line))
+			continue
+		}
+
+		// By default, save whatever we have as a paragraph
+		paragraph = append(paragraph, line)
+	}
+
+	// Flush whatever is left at the end of the file.
+	flushAll()
+
+	return page
+}
+
+// fillHolosceneDate tries to find a date in the format of "H.E." and
+// saves it as the page's date.
+func fillHolosceneDate(page *yunyun.Page) {
+	// No contents found?
+	if len(page.Contents) < 1 {
+		return
+	}
+	first := gana.First(page.Contents)
+	// Needs to be a simple text
+	if !first.IsParagraph() {
+		return
+	}
+	if !strings.HasSuffix(first.Paragraph, "H.E.") {
+		return
+	}
+	page.Date = first.Paragraph
+	page.DateHoloscene = true
+}
@@ -0,0 +1,167 @@
+package markdown
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/yunyun"
+)
+
+// parse parses the markdown as a page of a bare config.
+func parse(data string) *yunyun.Page {
+	return ParserMarkdown{Config: &alpha.DarknessConfig{}}.Do("page.md", data)
+}
+
+func TestDoFrontMatter(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		title  string
+		date   string
+		author string
+		first  string
+	}{
+		{
+			name:   "quoted values",
+			data:   "---\ntitle: \"Hello *there*\"\ndate: 2024-01-02\nauthor: 'Sandy'\n---\n\nSome text.",
+			title:  "Hello /there/",
+			date:   "2024-01-02",
+			author: "Sandy",
+			first:  "Some text.",
+		},
+		{
+			name:  "heading title",
+			data:  "# The title\n\nSome text.",
+			title: "The title",
+			first: "Some text.",
+		},
+		{
+			name:  "unclosed front matter",
+			data:  "---\ntitle: Nope",
+			title: "no title",
+			first: "title: Nope",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			page := parse(test.data)
+			if page.Title != test.title {
+				t.Errorf("title = %q, want %q", page.Title, test.title)
+			}
+			if test.date != "" && page.Date != test.date {
+				t.Errorf("date = %q, want %q", page.Date, test.date)
+			}
+			if page.Author != test.author {
+				t.Errorf("author = %q, want %q", page.Author, test.author)
+			}
+			if len(page.Contents) < 1 || page.Contents[len(page.Contents)-1].Paragraph != test.first {
+				t.Errorf("contents = %+v, want a paragraph %q", page.Contents, test.first)
+			}
+		})
+	}
+}
+
+func TestDoFences(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		lang string
+		code string
+	}{
+		{"backticks", "```go\nfmt.Println(\"*hi*\")\n```", "go", "fmt.Println(\"*hi*\")"},
+		{"tildes", "~~~\n~~not struck~~\n~~~", "", "~~not struck~~"},
+		{"longer fence", "````md\n```\ninner\n```\n````", "md", "```\ninner\n```"},
+		{"footnote lookalike", "```\n[^1]: stays in the code\n```\n\n[^1]: A note.", "", "[^1]: stays in the code"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			page := parse(test.data)
+			if len(page.Contents) != 1 || !page.Contents[0].IsSourceCode() {
+				t.Fatalf("contents = %+v, want a single source code block", page.Contents)
+			}
+			if got := page.Contents[0].SourceCodeLang; got != test.lang {
+				t.Errorf("lang = %q, want %q", got, test.lang)
+			}
+			if got := page.Contents[0].SourceCode; got != test.code {
+				t.Errorf("code = %q, want %q", got, test.code)
+			}
+		})
+	}
+}
+
+func TestDoTables(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		table   [][]string
+		headers bool
+	}{
+		{
+			name:    "headers",
+			data:    "| a | b |\n|---|:-:|\n| 1 | 2 |",
+			table:   [][]string{{"a", "b"}, {"1", "2"}},
+			headers: true,
+		},
+		{
+			name:  "escaped pipes",
+			data:  "| a \\| b | c |\n| `x|y` | **d** |",
+			table: [][]string{{"a | b", "c"}, {"=x|y=", "*d*"}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			page := parse(test.data)
+			if len(page.Contents) != 1 || !page.Contents[0].IsTable() {
+				t.Fatalf("contents = %+v, want a single table", page.Contents)
+			}
+			if got := page.Contents[0].Table; !reflect.DeepEqual(got, test.table) {
+				t.Errorf("table = %q, want %q", got, test.table)
+			}
+			if got := page.Contents[0].TableHeaders; got != test.headers {
+				t.Errorf("headers = %t, want %t", got, test.headers)
+			}
+		})
+	}
+}
+
+func TestDoLinks(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		link        string
+		title       string
+		description string
+		attributes  string
+	}{
+		{"link", "[Home](index.html)", "index.html", "Home", "Home", ""},
+		{"link with title", `[Home](index.html "Go home")`, "index.html", "Home", "Go home", ""},
+		{"image", `![A cat](cat.png "The cat")`, "cat.png", "A cat", "The cat", ""},
+		{"image without extension", "![A cat](https://example.com/cat)", "https://example.com/cat", "A cat", "A cat", "image"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			page := parse(test.data)
+			if len(page.Contents) != 1 || !page.Contents[0].IsLink() {
+				t.Fatalf("contents = %+v, want a single link", page.Contents)
+			}
+			link := page.Contents[0]
+			if link.Link != test.link || link.LinkTitle != test.title || link.LinkDescription != test.description || link.Attributes != test.attributes {
+				t.Errorf("link = %q %q %q %q, want %q %q %q %q",
+					link.Link, link.LinkTitle, link.LinkDescription, link.Attributes,
+					test.link, test.title, test.description, test.attributes)
+			}
+		})
+	}
+}
+
+func TestDoFootnotes(t *testing.T) {
+	page := parse("Text[^1] and more[^note].\n\n[^1]: The first.\n[^note]: The *second*.")
+	if len(page.Contents) != 1 || !page.Contents[0].IsParagraph() {
+		t.Fatalf("contents = %+v, want a single paragraph", page.Contents)
+	}
+	want := "Text[fn:: The first.] and more[fn:: The /second/.]."
+	if got := page.Contents[0].Paragraph; got != want {
+		t.Errorf("paragraph = %q, want %q", got, want)
+	}
+}
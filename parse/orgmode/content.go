@@ -114,6 +114,18 @@ func isSourceCodeEnd(line string) bool {
 	return strings.ToLower(line) == optionPrefix+optionEndSource
 }
 
+// unescapeSourceCode removes the comma that keeps a source code line
+// starting with `*` or `#` from being read as an org heading or option,
+// commas that escape a comma are removed one at a time, like in org.
+func unescapeSourceCode(line string) string {
+	code := strings.TrimLeft(line, " \t")
+	escaped := strings.TrimLeft(code, ",")
+	if len(escaped) == len(code) || !(strings.HasPrefix(escaped, "*") || strings.HasPrefix(escaped, "#")) {
+		return line
+	}
+	return line[:len(line)-len(code)] + code[1:]
+}
+
 // isHtmlExportBegin returns true if we are currently reading the start
 // of an html export block, false otherwise.
 func isHtmlExportBegin(line string) bool {
@@ -191,11 +203,31 @@ func extractDate(line string) string {
 	return extractOptionLabel(line, optionDate)
 }
 
+// extractUpdated extracts date `DATE` from `#+updated: DATE`.
+func extractUpdated(line string) string {
+	return extractOptionLabel(line, optionUpdated)
+}
+
 // extractDate extracts author `AUTHOR` from `#+author: AUTHOR`.
 func extractAuthor(line string) string {
 	return extractOptionLabel(line, optionAuthor)
 }
 
+// extractEnclosure extracts enclosure `ENCLOSURE` from `#+enclosure: ENCLOSURE`.
+func extractEnclosure(line string) string {
+	return extractOptionLabel(line, optionEnclosure)
+}
+
+// extractSeries extracts series `SERIES` from `#+series: SERIES`.
+func extractSeries(line string) string {
+	return extractOptionLabel(line, optionSeries)
+}
+
+// extractTags extracts tags `TAGS` from `#+tags: TAGS` or `#+filetags: TAGS`.
+func extractTags(line, option string) []string {
+	return yunyun.ParseTags(extractOptionLabel(line, option))
+}
+
 // extractGalleryFolder extracts gallery `FOLDER` from `#+begin_gallery FOLDER`.
 func extractGalleryFolder(line string) string {
 	path, err := extractCustomBlockOption(line, `path`, regexpPatternNoWhitespace)
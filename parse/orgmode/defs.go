@@ -22,11 +22,16 @@ const (
 	optionEndGallery   = "end_gallery"
 	optionCaption      = "caption:"
 	optionDate         = "date:"
+	optionUpdated      = "updated:"
 	optionHtmlHead     = "html_head:"
 	optionOptions      = "options:"
 	optionAttributes   = "attr_darkness:"
 	optionHtmlTags     = "html_tags:"
 	optionAuthor       = "author:"
+	optionEnclosure    = "enclosure:"
+	optionTags         = "tags:"
+	optionFiletags     = "filetags:"
+	optionSeries       = "series:"
 	horizontalLine     = "-----"
 
 	sectionLevelOne   = "* "
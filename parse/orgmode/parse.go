@@ -121,11 +121,16 @@ func (p ParserOrgmode) Do(
 		optionEndGallery: func(line string) { removeFlag(yunyun.InGalleryFlag) },
 		optionCaption:    func(line string) { caption = extractCaptionTitle(line) },
 		optionDate:       func(line string) { page.Date = extractDate(line) },
+		optionUpdated:    func(line string) { page.Updated = extractUpdated(line) },
 		optionHtmlHead:   func(line string) { page.HtmlHead = append(page.HtmlHead, extractHtmlHead(line)) },
 		optionOptions:    func(line string) { optionsStrings += extractOptions(line) + " " },
 		optionAttributes: func(line string) { attributes = extractAttributes(line) },
 		optionAuthor:     func(line string) { page.Author = extractAuthor(line) },
 		optionHtmlTags:   func(line string) { customHtmlTags = extractHtmlTags(line) },
+		optionEnclosure:  func(line string) { page.Enclosure = yunyun.ParseEnclosure(extractEnclosure(line)) },
+		optionTags:       func(line string) { page.AddTags(extractTags(line, optionTags)...) },
+		optionFiletags:   func(line string) { page.AddTags(extractTags(line, optionFiletags)...) },
+		optionSeries:     func(line string) { page.Series = yunyun.ParseSeries(extractSeries(line)) },
 	}
 
 	// Yunyun's markings default to orgmode
@@ -185,7 +190,7 @@ func (p ParserOrgmode) Do(
 				continue
 			}
 			// Save the context and continue
-			currentContext = previousContext + rawLine + "\n"
+			currentContext = previousContext + unescapeSourceCode(rawLine) + "\n"
 			continue
 		}
 		// Should we enter a source code environment?
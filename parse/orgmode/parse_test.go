@@ -0,0 +1,24 @@
+package orgmode
+
+import (
+	"testing"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/yunyun"
+)
+
+func TestDoSourceCodeEscapes(t *testing.T) {
+	data := "#+title: Code\n\n#+begin_src org\n,* Heading\n  ,#+title: Nested\n,,* Kept once\nf(a,#b)\n,,,\n#+end_src\n"
+	page := ParserOrgmode{Config: &alpha.DarknessConfig{}}.Do("page.org", data)
+	want := "* Heading\n  #+title: Nested\n,* Kept once\nf(a,#b)\n,,,"
+	for _, content := range page.Contents {
+		if content.Type != yunyun.TypeSourceCode {
+			continue
+		}
+		if content.SourceCode != want {
+			t.Errorf("source code = %q, want %q", content.SourceCode, want)
+		}
+		return
+	}
+	t.Errorf("no source code in %+v", page.Contents)
+}
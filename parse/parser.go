@@ -2,9 +2,11 @@ package parse
 
 import (
 	"log"
+	"path/filepath"
 
 	"github.com/thecsw/darkness/emilia/alpha"
 	"github.com/thecsw/darkness/emilia/puck"
+	"github.com/thecsw/darkness/parse/markdown"
 	"github.com/thecsw/darkness/parse/orgmode"
 	"github.com/thecsw/darkness/yunyun"
 )
@@ -15,14 +17,35 @@ type Parser interface {
 	Do(yunyun.RelativePathFile, string) *yunyun.Page
 }
 
-// BuildParser builds a parser based on the config.
-func BuildParser(conf *alpha.DarknessConfig) Parser {
-	var parser Parser
-	switch conf.Project.Input {
-	case puck.ExtensionOrgmode: // orgmode
-		parser = orgmode.ParserOrgmode{Config: conf}
-	default: // unknown
-		log.Fatalf("unknown input format: %s", conf.Project.Input)
+// registry maps input extensions to the functions that build their parsers.
+var registry = map[string]func(*alpha.DarknessConfig) Parser{
+	puck.ExtensionOrgmode:  func(conf *alpha.DarknessConfig) Parser { return orgmode.ParserOrgmode{Config: conf} },
+	puck.ExtensionMarkdown: func(conf *alpha.DarknessConfig) Parser { return markdown.ParserMarkdown{Config: conf} },
+}
+
+// BuildParser builds a parser for the given input extension.
+func BuildParser(conf *alpha.DarknessConfig, ext string) Parser {
+	builder, ok := registry[ext]
+	if !ok {
+		log.Fatalf("unknown input format: %s", ext)
+	}
+	return builder(conf)
+}
+
+// Parsers holds a parser for each of the project's input extensions.
+type Parsers map[string]Parser
+
+// BuildParsers builds parsers for all the input extensions in the config.
+func BuildParsers(conf *alpha.DarknessConfig) Parsers {
+	parsers := make(Parsers, len(conf.Project.Inputs))
+	for _, input := range conf.Project.Inputs {
+		parsers[input] = BuildParser(conf, input)
 	}
-	return parser
+	return parsers
+}
+
+// For returns the parser that should be used for the file, nil if
+// the file's extension is not one of the project's inputs.
+func (p Parsers) For(filename yunyun.FullPathFile) Parser {
+	return p[filepath.Ext(string(filename))]
 }
@@ -28,6 +28,14 @@ type Accoutrement struct {
 	Math AccoutrementFlip
 	// Toc enables/disables table of contents
 	Toc AccoutrementFlip
+	// Sitemap enables/disables listing the page in the sitemap.
+	Sitemap AccoutrementFlip
+	// SitemapPriority overrides the page's priority in the sitemap.
+	SitemapPriority string
+	// SitemapChangefreq overrides how often the page changes in the sitemap.
+	SitemapChangefreq string
+	// AutoIndex appends the list of the page's child pages.
+	AutoIndex AccoutrementFlip
 }
 
 // ExcludeHtmlHeadContains is a type to store excluded keywords for html head.
@@ -0,0 +1,74 @@
+package atom
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+const (
+	// AtomNamespace is the namespace of Atom 1.0 documents.
+	AtomNamespace = "http://www.w3.org/2005/Atom"
+
+	// AtomFormat is the date format used in Atom spec (RFC 3339).
+	AtomFormat = time.RFC3339
+
+	// AtomDocs is the Atom spec implemented.
+	AtomDocs = "https://datatracker.ietf.org/doc/html/rfc4287"
+)
+
+// Feed is the document (i.e., top-level) element of an Atom Feed
+// Document, acting as a container for metadata and data associated
+// with the feed. Its element children consist of metadata elements
+// followed by zero or more <entry> child elements.
+type Feed struct {
+	XMLName xml.Name `xml:"feed"`
+
+	// Must be "http://www.w3.org/2005/Atom"
+	Namespace string `xml:"xmlns,attr"`
+
+	// Natural language of the feed's text.
+	//
+	// Example: "en"
+	Lang string `xml:"xml:lang,attr,omitempty"`
+
+	// A permanent, universally unique identifier for the feed.
+	//
+	// Example: "<id>https://example.com/</id>"
+	Id string `xml:"id"`
+
+	// A human-readable title for the feed.
+	Title *Text `xml:"title"`
+
+	// A human-readable description or subtitle for the feed.
+	Subtitle *Text `xml:"subtitle,omitempty"`
+
+	// The most recent instant in time when the feed was modified
+	// in a way the publisher considers significant.
+	//
+	// Example: "<updated>2003-12-13T18:30:02Z</updated>"
+	Updated string `xml:"updated"`
+
+	// Authors of the feed. A feed must contain at least one author
+	// element unless all of the entry elements contain one.
+	Authors []Person `xml:"author,omitempty"`
+
+	// Links from the feed to web resources, a feed should contain
+	// a link with a rel attribute value of "self".
+	Links []Link `xml:"link"`
+
+	// Categories associated with the feed.
+	Categories []Category `xml:"category,omitempty"`
+
+	// Identifies the agent used to generate the feed.
+	Generator *Generator `xml:"generator,omitempty"`
+
+	// A small image which provides iconic visual identification
+	// for the feed, should be square.
+	Icon string `xml:"icon,omitempty"`
+
+	// Information about rights held in and over the feed.
+	Rights *Text `xml:"rights,omitempty"`
+
+	// Entries of the feed.
+	Entries []Entry `xml:"entry"`
+}
@@ -0,0 +1,16 @@
+package atom
+
+// Category conveys information about a category associated
+// with an entry or feed.
+//
+// Example: "<category term="blog" scheme="https://example.com/blog" label="Blog"/>"
+type Category struct {
+	// Identifies the category to which the entry or feed belongs, required.
+	Term string `xml:"term,attr"`
+
+	// An IRI that identifies a categorization scheme.
+	Scheme string `xml:"scheme,attr,omitempty"`
+
+	// A human-readable label for display in end-user applications.
+	Label string `xml:"label,attr,omitempty"`
+}
@@ -0,0 +1,47 @@
+package atom
+
+import "encoding/xml"
+
+// Entry represents an individual entry, acting as a container for
+// metadata and data associated with the entry. This element can
+// appear as a child of the <feed> element, or it can appear as the
+// document (i.e., top-level) element of a stand-alone Atom Entry Document.
+type Entry struct {
+	XMLName xml.Name `xml:"entry"`
+
+	// A permanent, universally unique identifier for the entry,
+	// aggregators use it to determine if an entry is new.
+	//
+	// Example: "<id>https://example.com/blog/hello</id>"
+	Id string `xml:"id"`
+
+	// A human-readable title for the entry.
+	Title *Text `xml:"title"`
+
+	// The most recent instant in time when the entry was modified
+	// in a way the publisher considers significant.
+	Updated string `xml:"updated"`
+
+	// An instant in time associated with an event early in the
+	// life cycle of the entry, usually the initial publication.
+	Published string `xml:"published,omitempty"`
+
+	// Authors of the entry, which default to the feed's authors.
+	Authors []Person `xml:"author,omitempty"`
+
+	// Links from the entry to web resources, an entry without
+	// content must have a link with a rel attribute of "alternate".
+	Links []Link `xml:"link"`
+
+	// Categories associated with the entry.
+	Categories []Category `xml:"category,omitempty"`
+
+	// A short summary, abstract, or excerpt of the entry.
+	Summary *Text `xml:"summary,omitempty"`
+
+	// Either contains or links to the content of the entry.
+	Content *Text `xml:"content,omitempty"`
+
+	// Information about rights held in and over the entry.
+	Rights *Text `xml:"rights,omitempty"`
+}
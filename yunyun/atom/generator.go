@@ -0,0 +1,16 @@
+package atom
+
+// Generator identifies the agent used to generate a feed,
+// for debugging and other purposes.
+//
+// Example: "<generator uri="https://example.com/" version="1.0">Example</generator>"
+type Generator struct {
+	// A human-readable name of the generating agent.
+	Value string `xml:",chardata"`
+
+	// An IRI that is relevant to the agent.
+	Uri string `xml:"uri,attr,omitempty"`
+
+	// The version of the generating agent.
+	Version string `xml:"version,attr,omitempty"`
+}
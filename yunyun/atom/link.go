@@ -0,0 +1,31 @@
+package atom
+
+const (
+	// LinkRelAlternate points to an alternate version of the resource,
+	// like the html page of an entry.
+	LinkRelAlternate = "alternate"
+	// LinkRelSelf points to the feed itself.
+	LinkRelSelf = "self"
+	// LinkRelEnclosure points to a potentially large related resource.
+	LinkRelEnclosure = "enclosure"
+)
+
+// Link defines a reference from an entry or feed to a web resource.
+//
+// Example: "<link rel="alternate" type="text/html" href="https://example.com/"/>"
+type Link struct {
+	// The link's IRI, required.
+	Href string `xml:"href,attr"`
+
+	// The link relation type, defaults to "alternate" when omitted.
+	Rel string `xml:"rel,attr,omitempty"`
+
+	// An advisory media type of the resource.
+	Type string `xml:"type,attr,omitempty"`
+
+	// Human-readable information about the link.
+	Title string `xml:"title,attr,omitempty"`
+
+	// An advisory length of the linked content in octets.
+	Length int64 `xml:"length,attr,omitempty"`
+}
@@ -0,0 +1,14 @@
+package atom
+
+// Person describes a person, corporation, or similar entity,
+// it's used for <author> and <contributor> elements.
+type Person struct {
+	// A human-readable name for the person, required.
+	Name string `xml:"name"`
+
+	// An IRI associated with the person, like a homepage.
+	Uri string `xml:"uri,omitempty"`
+
+	// An e-mail address associated with the person.
+	Email string `xml:"email,omitempty"`
+}
@@ -0,0 +1,20 @@
+package atom
+
+const (
+	// TextTypeText is plain text with no entity escaped html.
+	TextTypeText = "text"
+	// TextTypeHtml is entity escaped html.
+	TextTypeHtml = "html"
+)
+
+// Text is a human-readable text construct, like <title>, <summary>,
+// or <content>. Its type attribute tells how to interpret the value.
+//
+// Example: "<title type="text">Less: &lt;</title>"
+type Text struct {
+	// One of "text" or "html", defaults to "text" when omitted.
+	Type string `xml:"type,attr,omitempty"`
+
+	// The text itself, html is escaped by the encoder.
+	Value string `xml:",chardata"`
+}
@@ -164,8 +164,19 @@ func (c Content) IsDetails() bool { return HasFlag(&c.Options, InDetailsFlag) }
 // IsGallery returns true if content is a gallery, false otherwise.
 func (c Content) IsGallery() bool { return HasFlag(&c.Options, InGalleryFlag) }
 
+// IsPagination returns true if content navigates between the pages of a listing.
+func (c Content) IsPagination() bool { return HasFlag(&c.Options, InPaginationFlag) }
+
 // IsDropCap returns true if the first letter should be a drop cap, false otherwise.
 func (c Content) IsDropCap() bool { return HasFlag(&c.Options, InDropCapFlag) }
 
 // IsQuote returns true if the content is a quotation, false otherwise.
 func (c Content) IsQuote() bool { return HasFlag(&c.Options, InQuoteFlag) }
+
+// Clone returns a deep copy of the content.
+func (c *Content) Clone() *Content {
+	clone := *c
+	clone.List = append([]ListItem(nil), c.List...)
+	clone.Table = gana.Map(func(row []string) []string { return append([]string(nil), row...) }, c.Table)
+	return &clone
+}
@@ -0,0 +1,60 @@
+package yunyun
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Enclosure is a media file attached to the page, like an episode of
+// a podcast, which feeds can deliver along with the page.
+type Enclosure struct {
+	// To prevent unkeyed literars.
+	_ struct{}
+	// Link is either a url or a path relative to the page.
+	Link string
+	// Duration is the duration of the media, either in seconds
+	// or in `HH:MM:SS` (`MM:SS` also works).
+	Duration string
+	// Episode is the number of the episode.
+	Episode string
+	// Explicit is true if the media has explicit content, nil if
+	// it wasn't declared.
+	Explicit *bool
+}
+
+// ParseEnclosure parses an enclosure declaration, which is the link
+// followed by optional `key=value` options, like
+// `episode.mp3 duration=01:02:03 episode=4 explicit=true`.
+func ParseEnclosure(declaration string) *Enclosure {
+	fields := strings.Fields(declaration)
+	if len(fields) < 1 {
+		return nil
+	}
+	enclosure := &Enclosure{Link: fields[0]}
+	for _, field := range fields[1:] {
+		key, value, _ := strings.Cut(field, "=")
+		switch strings.ToLower(key) {
+		case "duration":
+			enclosure.Duration = value
+		case "episode":
+			enclosure.Episode = value
+		case "explicit":
+			explicit := value == "true" || value == "yes"
+			enclosure.Explicit = &explicit
+		}
+	}
+	return enclosure
+}
+
+// Seconds returns the duration in seconds, 0 if it's not set or malformed.
+func (e *Enclosure) Seconds() int64 {
+	seconds := int64(0)
+	for _, part := range strings.Split(e.Duration, ":") {
+		value, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return 0
+		}
+		seconds = seconds*60 + value
+	}
+	return seconds
+}
@@ -61,6 +61,8 @@ const (
 	InDropCapFlag
 	// InGalleryFlag is used internally to mark gallery states.
 	InGalleryFlag
+	// InPaginationFlag is used to mark the navigation between listing pages.
+	InPaginationFlag
 	// YunYunStartCustomFlags is used internally to mark last flag.
 	YunYunStartCustomFlags
 )
@@ -0,0 +1,32 @@
+package graph
+
+// Graph is the graph of the internal links of the website, the layout
+// is what most force-directed graph libraries take as is.
+type Graph struct {
+	// Nodes are the pages of the website.
+	Nodes []Node `json:"nodes"`
+
+	// Links are the links between the pages.
+	Links []Link `json:"links"`
+}
+
+// Node is a single page.
+type Node struct {
+	// Id is the location of the page, relative to the website.
+	Id string `json:"id"`
+
+	// Title is the title of the page without any formatting.
+	Title string `json:"title"`
+
+	// Url is the full url of the page.
+	Url string `json:"url"`
+}
+
+// Link is a link from one page to another.
+type Link struct {
+	// Source is the id of the page with the link.
+	Source string `json:"source"`
+
+	// Target is the id of the page the link points to.
+	Target string `json:"target"`
+}
@@ -0,0 +1,22 @@
+package jsonfeed
+
+// Attachment is a related resource of an item, like an audio
+// file of a podcast episode.
+type Attachment struct {
+	// The location of the attachment, required.
+	Url string `json:"url"`
+
+	// The type of the attachment, required.
+	//
+	// Example: "audio/mpeg"
+	MimeType string `json:"mime_type"`
+
+	// A name for the attachment.
+	Title string `json:"title,omitempty"`
+
+	// How large the file is in bytes.
+	SizeInBytes int64 `json:"size_in_bytes,omitempty"`
+
+	// How long the attachment takes to listen to or watch, in seconds.
+	DurationInSeconds int64 `json:"duration_in_seconds,omitempty"`
+}
@@ -0,0 +1,14 @@
+package jsonfeed
+
+// Author is the author of a feed or of an item, at least one
+// of the fields must be present.
+type Author struct {
+	// The author's name.
+	Name string `json:"name,omitempty"`
+
+	// The URL of a site owned by the author.
+	Url string `json:"url,omitempty"`
+
+	// The URL for an image for the author, should be square.
+	Avatar string `json:"avatar,omitempty"`
+}
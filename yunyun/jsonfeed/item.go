@@ -0,0 +1,45 @@
+package jsonfeed
+
+// Item is a single object of the feed, like a blog post.
+type Item struct {
+	// Unique for the item in the feed over time, required.
+	Id string `json:"id"`
+
+	// The URL of the resource described by the item, the permalink.
+	Url string `json:"url,omitempty"`
+
+	// Plain text title of the item.
+	Title string `json:"title,omitempty"`
+
+	// The HTML of the item, one of the contents is required.
+	ContentHtml string `json:"content_html,omitempty"`
+
+	// The plain text of the item, one of the contents is required.
+	ContentText string `json:"content_text,omitempty"`
+
+	// A plain text sentence or two describing the item.
+	Summary string `json:"summary,omitempty"`
+
+	// The URL of the main image for the item.
+	Image string `json:"image,omitempty"`
+
+	// The date in RFC 3339 format when the item was published.
+	//
+	// Example: "2010-02-07T14:04:00-05:00"
+	DatePublished string `json:"date_published,omitempty"`
+
+	// The date in RFC 3339 format when the item was modified.
+	DateModified string `json:"date_modified,omitempty"`
+
+	// Authors of the item, which default to the feed's authors.
+	Authors []Author `json:"authors,omitempty"`
+
+	// Plain text tags of the item.
+	Tags []string `json:"tags,omitempty"`
+
+	// The language of the item, if different from the feed's.
+	Language string `json:"language,omitempty"`
+
+	// Related resources, like podcast audio files.
+	Attachments []Attachment `json:"attachments,omitempty"`
+}
@@ -0,0 +1,50 @@
+package jsonfeed
+
+const (
+	// JsonFeedVersion is the url of the JSON Feed spec version implemented.
+	JsonFeedVersion = "https://jsonfeed.org/version/1.1"
+
+	// JsonFeedDocs is the JSON Feed spec implemented.
+	JsonFeedDocs = "https://www.jsonfeed.org/version/1.1/"
+
+	// JsonFeedMimeType is the mime type of JSON Feed documents.
+	JsonFeedMimeType = "application/feed+json"
+)
+
+// Feed is the top-level object of a JSON Feed document.
+type Feed struct {
+	// The URL of the version of the format the feed uses, required.
+	Version string `json:"version"`
+
+	// The name of the feed, which will often correspond to the name
+	// of the website, required.
+	Title string `json:"title"`
+
+	// The URL of the resource that the feed describes, usually
+	// the website's home page.
+	HomePageUrl string `json:"home_page_url,omitempty"`
+
+	// The URL of the feed, and serves as the unique identifier for the feed.
+	FeedUrl string `json:"feed_url,omitempty"`
+
+	// Provides more detail, beyond the title, on what the feed is about.
+	Description string `json:"description,omitempty"`
+
+	// The URL of an image for the feed suitable to be used in a timeline,
+	// much the way an avatar might be used, should be square.
+	Icon string `json:"icon,omitempty"`
+
+	// The URL of an image for the feed suitable to be used in a source list.
+	Favicon string `json:"favicon,omitempty"`
+
+	// Authors of the feed.
+	Authors []Author `json:"authors,omitempty"`
+
+	// The primary language for the feed in the format specified in RFC 5646.
+	//
+	// Example: "en-US"
+	Language string `json:"language,omitempty"`
+
+	// Items of the feed, required (but may be empty).
+	Items []Item `json:"items"`
+}
@@ -21,6 +21,8 @@ type Page struct {
 	Author string
 	// Date is the date of the page.
 	Date string
+	// Updated is the date the page was last updated (optional).
+	Updated string
 	// File is the original filename of the page (optional).
 	File RelativePathFile
 	// Contents is the contents of the page.
@@ -33,6 +35,19 @@ type Page struct {
 	HtmlHead []string
 	// Footnotes is the footnotes of the page.
 	Footnotes []string
+	// Enclosure is the media file attached to the page (optional).
+	Enclosure *Enclosure
+	// Tags are the tags of the page, in the order they were declared.
+	Tags []string
+	// Links are the extra link tags of the page, like the neighbours
+	// of a paginated listing.
+	Links []Link
+	// Series is the series the page is a part of (optional).
+	Series *Series
+	// Previous and Next are the page's neighbours (optional).
+	Previous, Next *Neighbour
+	// Backlinks are the pages that link to the page.
+	Backlinks []Neighbour
 	// DateHoloscene tells us whether the first paragraph
 	// on the page is given as holoscene date stamp.
 	DateHoloscene bool
@@ -112,3 +127,35 @@ func JoinPaths[T AnyPath](what ...T) T {
 func AnyPathsToStrings[T AnyPath](what []T) []string {
 	return gana.Map(func(t T) string { return string(t) }, what)
 }
+
+// Clone returns a deep copy of the page, so exporters that modify
+// the page don't step on each other when exporting the same page.
+func (p *Page) Clone() *Page {
+	clone := *p
+	if p.Accoutrement != nil {
+		accoutrement := *p.Accoutrement
+		accoutrement.ExcludeHtmlHeadContains = append(ExcludeHtmlHeadContains(nil), p.Accoutrement.ExcludeHtmlHeadContains...)
+		clone.Accoutrement = &accoutrement
+	}
+	if p.Enclosure != nil {
+		enclosure := *p.Enclosure
+		if p.Enclosure.Explicit != nil {
+			explicit := *p.Enclosure.Explicit
+			enclosure.Explicit = &explicit
+		}
+		clone.Enclosure = &enclosure
+	}
+	if p.Series != nil {
+		series := *p.Series
+		clone.Series = &series
+	}
+	clone.Contents = gana.Map(func(c *Content) *Content { return c.Clone() }, p.Contents)
+	clone.Scripts = append([]string(nil), p.Scripts...)
+	clone.Stylesheets = append([]string(nil), p.Stylesheets...)
+	clone.HtmlHead = append([]string(nil), p.HtmlHead...)
+	clone.Footnotes = append([]string(nil), p.Footnotes...)
+	clone.Tags = append([]string(nil), p.Tags...)
+	clone.Links = append([]Link(nil), p.Links...)
+	clone.Backlinks = append([]Neighbour(nil), p.Backlinks...)
+	return &clone
+}
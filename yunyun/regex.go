@@ -2,6 +2,8 @@ package yunyun
 
 import (
 	"regexp"
+	"strings"
+	"unicode"
 )
 
 // Markings is used to store the regex patterns for
@@ -171,7 +173,80 @@ func RemoveFormatting(what string) string {
 	what = NewLineRegexp.ReplaceAllString(what, `$1`)
 	// don't even show the footnotes
 	what = FootnoteRegexp.ReplaceAllString(what, ` `)
-	return what
+	return RemoveLiteralGuards(what)
+}
+
+// literalGuard is the word joiner, an invisible character that can't be
+// the border of a marking, so markings never start or end next to it.
+const literalGuard = "\u2060"
+
+var (
+	// markingCharacters are the characters that open or close markings.
+	markingCharacters = "*/=~+_^"
+	// markingBorderLeft matches a character a marking can start after.
+	markingBorderLeft = regexp.MustCompile(`^(?:[[:space:]]|` + darknessPunctLeft + `)$`)
+	// markingBorderRight matches a character a marking can end before.
+	markingBorderRight = regexp.MustCompile(`^(?:[[:space:]]|` + darknessPunctRight + `)$`)
+)
+
+// IsMarkingBorderLeft returns true if a marking can start right after the rune.
+func IsMarkingBorderLeft(r rune) bool {
+	return markingBorderLeft.MatchString(string(r))
+}
+
+// IsMarkingBorderRight returns true if a marking can end right before the rune.
+func IsMarkingBorderRight(r rune) bool {
+	return markingBorderRight.MatchString(string(r))
+}
+
+// Literal guards the marking characters of the text that would pair into
+// a marking, so they're shown as they are. It's meant for text that comes
+// from other markups, where these characters mean nothing.
+func Literal(text string) string {
+	runes := []rune(text)
+	opens := func(i int) bool {
+		return (i == 0 || IsMarkingBorderLeft(runes[i-1])) && i+1 < len(runes) && !unicode.IsSpace(runes[i+1])
+	}
+	closes := func(i int) bool {
+		return (i == len(runes)-1 || IsMarkingBorderRight(runes[i+1])) && i > 0 && !unicode.IsSpace(runes[i-1])
+	}
+	guardBefore, guardAfter := make([]bool, len(runes)), make([]bool, len(runes))
+	for i, r := range runes {
+		if !strings.ContainsRune(markingCharacters, r) || !opens(i) {
+			continue
+		}
+		// Superscripts and subscripts are closed by braces instead.
+		if rest := string(runes[i+1:]); (r == '^' || r == '_') && strings.HasPrefix(rest, "{{") && strings.Contains(rest[2:], "}}") {
+			guardBefore[i] = true
+		}
+		for j := i + 2; j < len(runes); j++ {
+			if pairedMarkings(r, runes[j]) && closes(j) {
+				guardBefore[i], guardAfter[j] = true, true
+			}
+		}
+	}
+	literal := &strings.Builder{}
+	for i, r := range runes {
+		if guardBefore[i] {
+			literal.WriteString(literalGuard)
+		}
+		literal.WriteRune(r)
+		if guardAfter[i] {
+			literal.WriteString(literalGuard)
+		}
+	}
+	return literal.String()
+}
+
+// pairedMarkings returns true if the characters open and close the same marking.
+func pairedMarkings(open, close rune) bool {
+	return open == close || (strings.ContainsRune("~=", open) && strings.ContainsRune("~=", close))
+}
+
+// RemoveLiteralGuards removes the guards left by `Literal`, which should
+// be done once the markings are processed.
+func RemoveLiteralGuards(text string) string {
+	return strings.ReplaceAll(text, literalGuard, "")
 }
 
 const (
@@ -23,5 +23,5 @@ type Category struct {
 	Value string `xml:",chardata"`
 
 	// Url to the category.
-	Domain string `xml:"domain,attr"`
+	Domain string `xml:"domain,attr,omitempty"`
 }
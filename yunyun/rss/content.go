@@ -0,0 +1,18 @@
+package rss
+
+import "encoding/xml"
+
+// ContentNamespace is the namespace of the content module, which
+// has to be declared on the <rss> element to use <content:encoded>.
+const ContentNamespace = "http://purl.org/rss/1.0/modules/content/"
+
+// ContentEncoded is the full content of an item, as opposed to the
+// synopsis in <description>. Its value is html, wrapped in CDATA.
+//
+// See more: https://web.resource.org/rss/1.0/modules/content/
+type ContentEncoded struct {
+	XMLName xml.Name `xml:"content:encoded"`
+
+	// The html content of the item.
+	Value string `xml:",cdata"`
+}
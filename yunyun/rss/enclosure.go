@@ -18,6 +18,6 @@ type Enclosure struct {
 	// Content type of the enclosure.
 	Type string `xml:"type,attr"`
 
-	// Length of the image in bytes.
-	Length int `xml:"length,attr"`
+	// Length of the enclosure in bytes.
+	Length int64 `xml:"length,attr"`
 }
@@ -23,11 +23,19 @@ type Item struct {
 	// You may include as many category elements as you need to, for different
 	// domains, and to have an item cross-referenced in different parts of the
 	// same domain.
-	Category *Category `xml:"category,omitempty"`
+	Categories []*Category `xml:"category,omitempty"`
+
+	// The full html content of the item, requires `ContentNamespace`
+	// to be set on the feed.
+	ContentEncoded *ContentEncoded `xml:"content:encoded,omitempty"`
 
 	// Describes a media object that is attached to the item.
 	Enclosure *Enclosure `xml:"enclosure,omitempty"`
 
+	// Podcast tags of the item, requires `ItunesNamespace` to be
+	// set on the feed.
+	*Itunes
+
 	// A string that uniquely identifies the item.
 	//
 	// guid stands for globally unique identifier. It's a string that
@@ -0,0 +1,23 @@
+package rss
+
+// ItunesNamespace is the namespace of Apple's podcast tags, which
+// has to be declared on the <rss> element to use <itunes:*> tags.
+//
+// See more: https://help.apple.com/itc/podcasts_connect/#/itcb54353390
+const ItunesNamespace = "http://www.itunes.com/dtds/podcast-1.0.dtd"
+
+// Itunes are the podcast tags of an item.
+type Itunes struct {
+	// The duration of an episode, either in seconds or in HH:MM:SS.
+	//
+	// Example: "<itunes:duration>1:02:03</itunes:duration>"
+	Duration string `xml:"itunes:duration,omitempty"`
+
+	// The episode parental advisory information, "true" or "false".
+	Explicit string `xml:"itunes:explicit,omitempty"`
+
+	// The episode number.
+	//
+	// Example: "<itunes:episode>4</itunes:episode>"
+	Episode string `xml:"itunes:episode,omitempty"`
+}
@@ -31,4 +31,10 @@ type RSS struct {
 
 	// Must be "2.0"
 	Version string `xml:"version,attr"`
+
+	// Declares the content module, needed if items have <content:encoded>.
+	ContentNamespace string `xml:"xmlns:content,attr,omitempty"`
+
+	// Declares the itunes namespace, needed if items have <itunes:*> tags.
+	ItunesNamespace string `xml:"xmlns:itunes,attr,omitempty"`
 }
@@ -0,0 +1,47 @@
+package search
+
+const (
+	// SearchVersion is the version of the index format, it's bumped
+	// whenever the widget can't read older indices anymore.
+	SearchVersion = 1
+)
+
+// Index is the search index of the whole website.
+type Index struct {
+	// Version is the `SearchVersion` the index was built with.
+	Version int `json:"version"`
+
+	// Documents are the indexed pages, terms refer to them by their
+	// position in this list.
+	Documents []Document `json:"documents"`
+
+	// Terms map the stemmed terms to flat pairs of document positions
+	// and weights, so "word": [0, 3, 4, 1] means that "word" has the
+	// weight of 3 in the first document and 1 in the fifth one.
+	Terms map[string][]int `json:"terms"`
+}
+
+// Document is a single indexed page.
+type Document struct {
+	// Title is the title of the page.
+	Title string `json:"title"`
+
+	// Url is the full url of the page.
+	Url string `json:"url"`
+
+	// Description is the short summary of the page.
+	Description string `json:"description,omitempty"`
+
+	// Headings are the headings of the page, so results can link
+	// straight to the matching section.
+	Headings []Heading `json:"headings,omitempty"`
+}
+
+// Heading is a heading of an indexed page.
+type Heading struct {
+	// Text is the heading without any formatting.
+	Text string `json:"text"`
+
+	// Id is the anchor of the heading on the page.
+	Id string `json:"id"`
+}
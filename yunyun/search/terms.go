@@ -0,0 +1,76 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// minStemLength is the shortest stem that a suffix can be stripped to.
+const minStemLength = 3
+
+// suffixes are the stemming rules, the first matching one is applied.
+// The widget applies the very same rules to the queries, so both must
+// be changed together.
+var suffixes = []struct {
+	suffix      string
+	replacement string
+}{
+	{"ational", "ate"},
+	{"ization", "ize"},
+	{"fulness", "ful"},
+	{"ousness", "ous"},
+	{"iveness", "ive"},
+	{"ingly", ""},
+	{"edly", ""},
+	{"ments", ""},
+	{"ment", ""},
+	{"ness", ""},
+	{"ies", "y"},
+	{"ing", ""},
+	{"ly", ""},
+	{"ed", ""},
+	{"ss", "ss"},
+	{"s", ""},
+}
+
+// stopWords are too common to be worth indexing.
+var stopWords = map[string]struct{}{
+	"a": {}, "an": {}, "and": {}, "are": {}, "as": {}, "at": {}, "be": {},
+	"but": {}, "by": {}, "for": {}, "from": {}, "if": {}, "in": {}, "into": {},
+	"is": {}, "it": {}, "its": {}, "of": {}, "on": {}, "or": {}, "so": {},
+	"that": {}, "the": {}, "their": {}, "then": {}, "there": {}, "these": {},
+	"they": {}, "this": {}, "to": {}, "was": {}, "were": {}, "will": {},
+	"with": {},
+}
+
+// Terms splits the text into words and returns their stems, stop
+// words and single letters are left out.
+func Terms(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		if _, stop := stopWords[word]; stop || utf8.RuneCountInString(word) < 2 {
+			continue
+		}
+		terms = append(terms, Stem(word))
+	}
+	return terms
+}
+
+// Stem strips the common english suffixes off of the lowercase word.
+func Stem(word string) string {
+	for _, rule := range suffixes {
+		if !strings.HasSuffix(word, rule.suffix) {
+			continue
+		}
+		stem := strings.TrimSuffix(word, rule.suffix)
+		if utf8.RuneCountInString(stem) < minStemLength {
+			return word
+		}
+		return stem + rule.replacement
+	}
+	return word
+}
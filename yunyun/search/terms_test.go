@@ -0,0 +1,62 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStem(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"relational", "relate"},
+		{"organization", "organize"},
+		{"hopefulness", "hopeful"},
+		{"callousness", "callous"},
+		{"decisiveness", "decisive"},
+		{"knowingly", "know"},
+		{"markedly", "mark"},
+		{"arguments", "argu"},
+		{"argument", "argu"},
+		{"darkness", "dark"},
+		{"stories", "story"},
+		{"sailing", "sail"},
+		{"quickly", "quick"},
+		{"walked", "walk"},
+		{"glass", "glass"},
+		{"whales", "whale"},
+		{"whale", "whale"},
+		// Stems can't get shorter than three letters.
+		{"sing", "sing"},
+		{"bed", "bed"},
+		{"ties", "ties"},
+		{"us", "us"},
+	}
+	for _, test := range tests {
+		if got := Stem(test.word); got != test.want {
+			t.Errorf("Stem(%q) = %q, want %q", test.word, got, test.want)
+		}
+	}
+}
+
+func TestTerms(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"The Whales are sailing", []string{"whale", "sail"}},
+		{"Call me Ishmael.", []string{"call", "me", "ishmael"}},
+		{"a b c x y z", []string{}},
+		{"it is what it is", []string{"what"}},
+		{"rock'n'roll, jazz-funk", []string{"rock", "roll", "jazz", "funk"}},
+		{"version 2 of 42 apps", []string{"version", "42", "app"}},
+		{"Ünïcödé wörds", []string{"ünïcödé", "wörd"}},
+		{"", []string{}},
+	}
+	for _, test := range tests {
+		if got := Terms(test.text); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Terms(%q) = %q, want %q", test.text, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package yunyun
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Series is a multi-part series that the page is a part of.
+type Series struct {
+	// To prevent unkeyed literars.
+	_ struct{}
+	// Name is the name of the series, pages with the same slug of
+	// the name are in the same series.
+	Name string
+	// Order is the position of the page in the series, pages without
+	// it are ordered by their dates after the ones with it.
+	Order int
+}
+
+// ParseSeries parses a series declaration, which is the name followed
+// by an optional `order=N`, like `Moby Dick order=2`.
+func ParseSeries(declaration string) *Series {
+	series := &Series{}
+	name := make([]string, 0, 4)
+	for _, field := range strings.Fields(declaration) {
+		if value, found := strings.CutPrefix(strings.ToLower(field), "order="); found {
+			if order, err := strconv.Atoi(value); err == nil && order > 0 {
+				series.Order = order
+				continue
+			}
+		}
+		name = append(name, field)
+	}
+	if len(name) < 1 {
+		return nil
+	}
+	series.Name = strings.Join(name, " ")
+	return series
+}
+
+// Neighbour is another page that the page points to, like the page
+// right before or after it, or a page that links to it.
+type Neighbour struct {
+	// To prevent unkeyed literars.
+	_ struct{}
+	// Title is the title of the neighbour.
+	Title string
+	// Location is the location of the neighbour.
+	Location RelativePathDir
+}
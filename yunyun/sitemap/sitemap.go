@@ -0,0 +1,47 @@
+package sitemap
+
+import "encoding/xml"
+
+const (
+	// SitemapNamespace is the namespace of sitemap documents.
+	SitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+	// SitemapFormat is the date format used in sitemaps (W3C Datetime).
+	SitemapFormat = "2006-01-02"
+
+	// SitemapDocs is the sitemap spec implemented.
+	SitemapDocs = "https://www.sitemaps.org/protocol.html"
+)
+
+// UrlSet encapsulates the file and references the current protocol standard.
+type UrlSet struct {
+	XMLName xml.Name `xml:"urlset"`
+
+	// Must be "http://www.sitemaps.org/schemas/sitemap/0.9"
+	Namespace string `xml:"xmlns,attr"`
+
+	// Urls are the entries of the sitemap.
+	Urls []Url `xml:"url"`
+}
+
+// Url is the parent tag for each entry.
+type Url struct {
+	// Url of the page, it must begin with the protocol and be
+	// less than 2,048 characters, required.
+	//
+	// Example: "<loc>https://www.example.com/</loc>"
+	Loc string `xml:"loc"`
+
+	// The date of last modification of the page in W3C Datetime.
+	//
+	// Example: "<lastmod>2005-01-01</lastmod>"
+	Lastmod string `xml:"lastmod,omitempty"`
+
+	// How frequently the page is likely to change, one of always,
+	// hourly, daily, weekly, monthly, yearly, or never.
+	Changefreq string `xml:"changefreq,omitempty"`
+
+	// The priority of this url relative to other urls on the site,
+	// from 0.0 to 1.0, where the default is 0.5.
+	Priority string `xml:"priority,omitempty"`
+}
@@ -0,0 +1,63 @@
+package yunyun
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ParseTags parses the tags from orgmode's `:emacs:go:`, or the more
+// common `emacs, go` and `[emacs, go]`, empty tags are left out.
+func ParseTags(what string) []string {
+	what = strings.Trim(strings.TrimSpace(what), "[]")
+	separator := ","
+	if !strings.Contains(what, ",") && strings.Contains(what, ":") {
+		separator = ":"
+	}
+	tags := make([]string, 0, 4)
+	for _, tag := range strings.Split(what, separator) {
+		tag = strings.Trim(strings.TrimSpace(tag), `"'`)
+		if len(tag) > 0 {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// TagSlug returns the url-friendly version of the tag, tags with the
+// same slug are the same tag.
+func TagSlug(tag string) string {
+	slug := strings.Builder{}
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(tag)) {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			slug.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && slug.Len() > 0 {
+			slug.WriteRune('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(slug.String(), "-")
+}
+
+// AddTags adds the tags to the page, skipping the ones it already has.
+func (p *Page) AddTags(tags ...string) {
+	for _, tag := range tags {
+		slug := TagSlug(tag)
+		if len(slug) < 1 {
+			continue
+		}
+		found := false
+		for _, existing := range p.Tags {
+			if TagSlug(existing) == slug {
+				found = true
+				break
+			}
+		}
+		if !found {
+			p.Tags = append(p.Tags, tag)
+		}
+	}
+}